@@ -0,0 +1,204 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+)
+
+// used to determine which aggregation to run
+type aggregationType int
+
+const (
+	countAggregation aggregationType = iota
+	sumAggregation
+	averageAggregation
+)
+
+// A Firevault Aggregation represents a single aggregation
+// (count, sum or average), run over Firestore documents
+// which match a Query.
+//
+// To create an Aggregation, use the AggregateCount,
+// AggregateSum or AggregateAverage functions.
+type Aggregation struct {
+	alias string
+	kind  aggregationType
+	path  string
+}
+
+// An AggregationResult holds the result of a single
+// Aggregation.
+type AggregationResult struct {
+	value *firestorepb.Value
+}
+
+// AggregateCount returns an Aggregation which counts
+// matching documents. Its result is accessed using the
+// provided alias.
+func AggregateCount(alias string) Aggregation {
+	return Aggregation{alias, countAggregation, ""}
+}
+
+// AggregateSum returns an Aggregation which sums the
+// numeric values of the field at the path (using
+// dot-separation) in matching documents. Non-numeric
+// values are ignored. Its result is accessed using the
+// provided alias.
+func AggregateSum(alias string, path string) Aggregation {
+	return Aggregation{alias, sumAggregation, path}
+}
+
+// AggregateAverage returns an Aggregation which averages
+// the numeric values of the field at the path (using
+// dot-separation) in matching documents. Non-numeric
+// values are ignored. Its result is accessed using the
+// provided alias.
+func AggregateAverage(alias string, path string) Aggregation {
+	return Aggregation{alias, averageAggregation, path}
+}
+
+// Int returns the result as an integer. Float results
+// (e.g. averages) are truncated.
+func (r AggregationResult) Int() int64 {
+	if v, ok := r.value.GetValueType().(*firestorepb.Value_DoubleValue); ok {
+		return int64(v.DoubleValue)
+	}
+
+	return r.value.GetIntegerValue()
+}
+
+// Float returns the result as a float. Sums of integers
+// larger than 2^53 may lose precision - use Int instead.
+func (r AggregationResult) Float() float64 {
+	if v, ok := r.value.GetValueType().(*firestorepb.Value_IntegerValue); ok {
+		return float64(v.IntegerValue)
+	}
+
+	return r.value.GetDoubleValue()
+}
+
+// IsNull reports whether the aggregation had no result
+// (e.g. the average of a field which no matching document
+// holds a numeric value for).
+func (r AggregationResult) IsNull() bool {
+	_, ok := r.value.GetValueType().(*firestorepb.Value_NullValue)
+	return r.value == nil || ok
+}
+
+// Find the sum of the numeric values of the field at the path
+// (using dot-separation), in Firestore documents which match
+// provided Query.
+//
+// The sum is an integer if all summed values are integers, so
+// use the result's Int method for integer fields (to keep large
+// sums exact), and its Float method otherwise. It's 0 if no
+// documents match the Query.
+func (c *CollectionRef[T]) Sum(ctx context.Context, query Query, path string) (AggregationResult, error) {
+	if c == nil {
+		return AggregationResult{}, errors.New("firevault: nil CollectionRef")
+	}
+
+	results, err := c.aggregate(ctx, query, AggregateSum("sum", path))
+	if err != nil {
+		return AggregationResult{}, err
+	}
+
+	return results["sum"], nil
+}
+
+// Find the average of the numeric values of the field at the path
+// (using dot-separation), in Firestore documents which match
+// provided Query.
+//
+// Returns 0 if no documents hold a numeric value for the field.
+func (c *CollectionRef[T]) Average(ctx context.Context, query Query, path string) (float64, error) {
+	if c == nil {
+		return 0, errors.New("firevault: nil CollectionRef")
+	}
+
+	results, err := c.aggregate(ctx, query, AggregateAverage("average", path))
+	if err != nil {
+		return 0, err
+	}
+
+	return results["average"].Float(), nil
+}
+
+// Run multiple aggregations over Firestore documents which match
+// provided Query, in a single request.
+//
+// The results are keyed by the aliases of the aggregations.
+//
+// If the Query specifies IDs, the aggregations are run over
+// the documents with those IDs (up to 30, Firestore's limit
+// for "in" filters).
+func (c *CollectionRef[T]) Aggregate(
+	ctx context.Context,
+	query Query,
+	aggregations ...Aggregation,
+) (map[string]AggregationResult, error) {
+	if c == nil {
+		return nil, errors.New("firevault: nil CollectionRef")
+	}
+
+	if len(aggregations) == 0 {
+		return nil, errors.New("firevault: no aggregations provided")
+	}
+
+	return c.aggregate(ctx, query, aggregations...)
+}
+
+// run the aggregations and extract their results
+func (c *CollectionRef[T]) aggregate(
+	ctx context.Context,
+	query Query,
+	aggregations ...Aggregation,
+) (map[string]AggregationResult, error) {
+	baseQuery, err := c.filterQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	aggQuery := baseQuery.NewAggregationQuery()
+
+	for _, agg := range aggregations {
+		switch agg.kind {
+		case countAggregation:
+			aggQuery = aggQuery.WithCount(agg.alias)
+		case sumAggregation:
+			aggQuery = aggQuery.WithSum(agg.path, agg.alias)
+		case averageAggregation:
+			aggQuery = aggQuery.WithAvg(agg.path, agg.alias)
+		}
+	}
+
+	results, err := aggQuery.Get(ctx)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return aggregationResults(results, aggregations)
+}
+
+// extract the aggregations' results, keyed by their aliases
+func aggregationResults(
+	results firestore.AggregationResult,
+	aggregations []Aggregation,
+) (map[string]AggregationResult, error) {
+	aggResults := make(map[string]AggregationResult, len(aggregations))
+
+	for _, agg := range aggregations {
+		value, ok := results[agg.alias].(*firestorepb.Value)
+		if !ok {
+			return nil, fmt.Errorf("firevault: couldn't get alias %s from results", agg.alias)
+		}
+
+		aggResults[agg.alias] = AggregationResult{value}
+	}
+
+	return aggResults, nil
+}
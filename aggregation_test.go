@@ -0,0 +1,88 @@
+package firevault
+
+import (
+	"testing"
+
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestAggregationResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     *firestorepb.Value
+		wantInt   int64
+		wantFloat float64
+		wantNull  bool
+	}{
+		{
+			name:      "Integer value",
+			value:     &firestorepb.Value{ValueType: &firestorepb.Value_IntegerValue{IntegerValue: 42}},
+			wantInt:   42,
+			wantFloat: 42,
+		},
+		{
+			name:      "Large integer value",
+			value:     &firestorepb.Value{ValueType: &firestorepb.Value_IntegerValue{IntegerValue: 1<<53 + 1}},
+			wantInt:   1<<53 + 1,
+			wantFloat: 1 << 53,
+		},
+		{
+			name:      "Double value",
+			value:     &firestorepb.Value{ValueType: &firestorepb.Value_DoubleValue{DoubleValue: 2.5}},
+			wantInt:   2,
+			wantFloat: 2.5,
+		},
+		{
+			name:     "Null value",
+			value:    &firestorepb.Value{ValueType: &firestorepb.Value_NullValue{NullValue: structpb.NullValue_NULL_VALUE}},
+			wantNull: true,
+		},
+		{
+			name:     "Missing value",
+			value:    nil,
+			wantNull: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AggregationResult{tt.value}
+
+			if got := result.Int(); got != tt.wantInt {
+				t.Errorf("AggregationResult.Int() = %v, want %v", got, tt.wantInt)
+			}
+			if got := result.Float(); got != tt.wantFloat {
+				t.Errorf("AggregationResult.Float() = %v, want %v", got, tt.wantFloat)
+			}
+			if got := result.IsNull(); got != tt.wantNull {
+				t.Errorf("AggregationResult.IsNull() = %v, want %v", got, tt.wantNull)
+			}
+		})
+	}
+}
+
+func TestAggregationResults(t *testing.T) {
+	// 2^53 + 1 can't be represented as a float64
+	sum := &firestorepb.Value{ValueType: &firestorepb.Value_IntegerValue{IntegerValue: 9007199254740993}}
+	count := &firestorepb.Value{ValueType: &firestorepb.Value_IntegerValue{IntegerValue: 3}}
+
+	aggregations := []Aggregation{AggregateSum("sum", "views"), AggregateCount("count")}
+
+	results, err := aggregationResults(map[string]interface{}{"sum": sum, "count": count}, aggregations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := results["sum"].Int(); got != 9007199254740993 {
+		t.Errorf("Expected integer sum to be exact, got %d", got)
+	}
+	if got := results["count"].Int(); got != 3 {
+		t.Errorf("Expected count of 3, got %d", got)
+	}
+
+	_, err = aggregationResults(map[string]interface{}{"sum": sum}, aggregations)
+	if err == nil {
+		t.Error("Expected error for missing alias")
+	}
+}
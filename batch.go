@@ -0,0 +1,202 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// the max number of writes Firestore allows in a single commit
+const maxBatchWrites = 500
+
+// A Firevault Batch holds multiple validated writes, across
+// multiple collections, which are applied atomically
+// (all or nothing) once committed.
+//
+// A Batch is not safe for concurrent use.
+type Batch struct {
+	connection *Connection
+	writes     []batchWrite
+}
+
+// a single write in a Batch
+type batchWrite func(*firestore.Transaction) error
+
+// A Firevault BatchCollectionRef holds a reference to a
+// Firestore Collection and allows for the adding of
+// validated writes to a Batch.
+type BatchCollectionRef[T interface{}] struct {
+	collection *CollectionRef[T]
+	batch      *Batch
+}
+
+// Create a new Batch instance.
+//
+// A Firevault Batch holds multiple validated writes, across
+// multiple collections, which are applied atomically
+// (all or nothing) once committed.
+func (c *Connection) Batch() *Batch {
+	if c == nil {
+		return nil
+	}
+
+	return &Batch{c, nil}
+}
+
+// Len returns the number of writes added to the Batch.
+func (b *Batch) Len() int {
+	if b == nil {
+		return 0
+	}
+
+	return len(b.writes)
+}
+
+// Commit applies all writes added to the Batch atomically.
+//
+// If the Batch holds more than 500 writes (Firestore's limit),
+// ErrBatchTooLarge is returned and no writes are applied.
+//
+// Once committed successfully, the Batch is emptied and can
+// be reused.
+func (b *Batch) Commit(ctx context.Context) error {
+	if b == nil || b.connection == nil || b.connection.client == nil {
+		return errors.New("firevault: nil Batch or Connection")
+	}
+
+	if len(b.writes) == 0 {
+		return nil
+	}
+
+	if len(b.writes) > maxBatchWrites {
+		return fmt.Errorf("%w (%d writes)", ErrBatchTooLarge, len(b.writes))
+	}
+
+	// a write-only transaction is committed atomically in a single request
+	err := b.connection.client.RunTransaction(ctx, func(_ context.Context, tx *firestore.Transaction) error {
+		for _, write := range b.writes {
+			err := write(tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return wrapError(err)
+	}
+
+	b.writes = nil
+	return nil
+}
+
+// WithBatch returns a BatchCollectionRef, which adds all
+// writes to the provided Batch, instead of applying them
+// immediately.
+//
+// Returns nil if the Batch was created by a different
+// Connection.
+func (c *CollectionRef[T]) WithBatch(batch *Batch) *BatchCollectionRef[T] {
+	if c == nil || batch == nil || batch.connection != c.connection {
+		return nil
+	}
+
+	return &BatchCollectionRef[T]{c, batch}
+}
+
+// Create adds the creation of a Firestore document with provided
+// data (after validation) to the Batch, and returns its ID.
+//
+// If a custom ID is specified (using Options), and a document
+// with that ID already exists, the Batch commit fails with
+// ErrAlreadyExists.
+func (b *BatchCollectionRef[T]) Create(ctx context.Context, data *T, opts ...Options) (string, error) {
+	if b == nil {
+		return "", errors.New("firevault: nil BatchCollectionRef")
+	}
+
+	valOptions, id, _ := b.collection.parseOptions(create, opts...)
+
+	dataMap, err := b.collection.connection.validator.validate(ctx, data, valOptions)
+	if err != nil {
+		return "", err
+	}
+
+	docRef := b.collection.ref.NewDoc()
+	if id != "" {
+		docRef = b.collection.ref.Doc(id)
+	}
+
+	b.batch.writes = append(b.batch.writes, func(tx *firestore.Transaction) error {
+		return tx.Create(docRef, dataMap)
+	})
+
+	return docRef.ID, nil
+}
+
+// Update adds the updating of all Firestore documents which match
+// provided Query (after data validation) to the Batch.
+//
+// If the Query doesn't specify IDs, the matching documents are
+// fetched immediately (i.e. not when the Batch is committed).
+//
+// If any of the documents do not exist, the Batch commit fails
+// with ErrNotFound. If any of them have changed since the version
+// specified with the IfUnchangedSince or IfMatch options, the
+// Batch commit fails with ErrFailedPrecondition.
+func (b *BatchCollectionRef[T]) Update(ctx context.Context, query Query, data *T, opts ...Options) error {
+	if b == nil {
+		return errors.New("firevault: nil BatchCollectionRef")
+	}
+
+	updates, err := b.collection.prepareUpdates(ctx, data, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return b.addWrites(ctx, query, func(docRef *firestore.DocumentRef) batchWrite {
+		preconds := b.collection.preconditions(docRef, opts...)
+
+		return func(tx *firestore.Transaction) error {
+			return tx.Update(docRef, updates, preconds...)
+		}
+	})
+}
+
+// Delete adds the deletion of all Firestore documents which match
+// provided Query to the Batch.
+//
+// If the Query doesn't specify IDs, the matching documents are
+// fetched immediately (i.e. not when the Batch is committed).
+func (b *BatchCollectionRef[T]) Delete(ctx context.Context, query Query, opts ...Options) error {
+	if b == nil {
+		return errors.New("firevault: nil BatchCollectionRef")
+	}
+
+	return b.addWrites(ctx, query, func(docRef *firestore.DocumentRef) batchWrite {
+		preconds := b.collection.preconditions(docRef, opts...)
+
+		return func(tx *firestore.Transaction) error {
+			return tx.Delete(docRef, preconds...)
+		}
+	})
+}
+
+// add a write for every document which matches the query
+func (b *BatchCollectionRef[T]) addWrites(
+	ctx context.Context,
+	query Query,
+	write func(*firestore.DocumentRef) batchWrite,
+) error {
+	return b.collection.forEachRef(ctx, query, nil, func(docRef *firestore.DocumentRef) error {
+		b.batch.writes = append(b.batch.writes, write(docRef))
+		return nil
+	})
+}
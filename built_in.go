@@ -1,11 +1,24 @@
 package firevault
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"math"
+	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 var builtInValidators = map[string]ValidationFn{
@@ -16,6 +29,183 @@ var builtInValidators = map[string]ValidationFn{
 	"email":             validateEmail,
 	"max":               validateMax,
 	"min":               validateMin,
+	"len":               validateLen,
+	"eq":                validateEq,
+	"ne":                validateNe,
+	"gt":                validateGt,
+	"gte":               validateGte,
+	"lt":                validateLt,
+	"lte":               validateLte,
+	"oneof":             validateOneOf,
+	"url":               validateURL,
+	"uri":               validateURI,
+	"uuid":              validateUUID,
+	"alpha":             validateAlpha,
+	"alphanum":          validateAlphanum,
+	"numeric":           validateNumeric,
+	"hexcolor":          validateHexColor,
+	"e164":              validateE164,
+	"iso3166":           validateISO3166,
+	"ip":                validateIP,
+	"cidr":              validateCIDR,
+	"contains":          validateContains,
+	"startswith":        validateStartsWith,
+	"endswith":          validateEndsWith,
+	"unique":            validateUnique,
+	"json":              validateJSON,
+	"base64":            validateBase64,
+}
+
+var builtInTransformations = map[string]transformationFn{
+	"trim":                 transformTrim,
+	"lowercase":            transformLowercase,
+	"uppercase":            transformUppercase,
+	"title":                transformTitle,
+	"slugify":              transformSlugify,
+	"normalize_email":      transformNormalizeEmail,
+	"normalize_phone_e164": transformNormalizePhoneE164,
+	"unicode_nfc":          transformUnicodeNFC,
+	"truncate":             transformTruncate,
+	"round":                transformRound,
+	"utc":                  transformUTC,
+	"dedupe":               transformDedupe,
+}
+
+var (
+	emailRegex    = regexp.MustCompile("^(?:(?:(?:(?:[a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+(?:\\.([a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+)*)|(?:(?:\\x22)(?:(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(?:\\x20|\\x09)+)?(?:(?:[\\x01-\\x08\\x0b\\x0c\\x0e-\\x1f\\x7f]|\\x21|[\\x23-\\x5b]|[\\x5d-\\x7e]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[\\x01-\\x09\\x0b\\x0c\\x0d-\\x7f]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}]))))*(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(\\x20|\\x09)+)?(?:\\x22))))@(?:(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.)+(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.?$")
+	uuidRegex     = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+	alphaRegex    = regexp.MustCompile("^[a-zA-Z]+$")
+	alphanumRegex = regexp.MustCompile("^[a-zA-Z0-9]+$")
+	numericRegex  = regexp.MustCompile(`^[-+]?[0-9]+(?:\.[0-9]+)?$`)
+	hexColorRegex = regexp.MustCompile("^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$")
+	e164Regex     = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+)
+
+// ISO 3166-1 alpha-2 country codes
+var iso3166Codes = map[string]bool{
+	"AD": true, "AE": true, "AF": true, "AG": true, "AI": true, "AL": true, "AM": true, "AO": true,
+	"AQ": true, "AR": true, "AS": true, "AT": true, "AU": true, "AW": true, "AX": true, "AZ": true,
+	"BA": true, "BB": true, "BD": true, "BE": true, "BF": true, "BG": true, "BH": true, "BI": true,
+	"BJ": true, "BL": true, "BM": true, "BN": true, "BO": true, "BQ": true, "BR": true, "BS": true,
+	"BT": true, "BV": true, "BW": true, "BY": true, "BZ": true, "CA": true, "CC": true, "CD": true,
+	"CF": true, "CG": true, "CH": true, "CI": true, "CK": true, "CL": true, "CM": true, "CN": true,
+	"CO": true, "CR": true, "CU": true, "CV": true, "CW": true, "CX": true, "CY": true, "CZ": true,
+	"DE": true, "DJ": true, "DK": true, "DM": true, "DO": true, "DZ": true, "EC": true, "EE": true,
+	"EG": true, "EH": true, "ER": true, "ES": true, "ET": true, "FI": true, "FJ": true, "FK": true,
+	"FM": true, "FO": true, "FR": true, "GA": true, "GB": true, "GD": true, "GE": true, "GF": true,
+	"GG": true, "GH": true, "GI": true, "GL": true, "GM": true, "GN": true, "GP": true, "GQ": true,
+	"GR": true, "GS": true, "GT": true, "GU": true, "GW": true, "GY": true, "HK": true, "HM": true,
+	"HN": true, "HR": true, "HT": true, "HU": true, "ID": true, "IE": true, "IL": true, "IM": true,
+	"IN": true, "IO": true, "IQ": true, "IR": true, "IS": true, "IT": true, "JE": true, "JM": true,
+	"JO": true, "JP": true, "KE": true, "KG": true, "KH": true, "KI": true, "KM": true, "KN": true,
+	"KP": true, "KR": true, "KW": true, "KY": true, "KZ": true, "LA": true, "LB": true, "LC": true,
+	"LI": true, "LK": true, "LR": true, "LS": true, "LT": true, "LU": true, "LV": true, "LY": true,
+	"MA": true, "MC": true, "MD": true, "ME": true, "MF": true, "MG": true, "MH": true, "MK": true,
+	"ML": true, "MM": true, "MN": true, "MO": true, "MP": true, "MQ": true, "MR": true, "MS": true,
+	"MT": true, "MU": true, "MV": true, "MW": true, "MX": true, "MY": true, "MZ": true, "NA": true,
+	"NC": true, "NE": true, "NF": true, "NG": true, "NI": true, "NL": true, "NO": true, "NP": true,
+	"NR": true, "NU": true, "NZ": true, "OM": true, "PA": true, "PE": true, "PF": true, "PG": true,
+	"PH": true, "PK": true, "PL": true, "PM": true, "PN": true, "PR": true, "PS": true, "PT": true,
+	"PW": true, "PY": true, "QA": true, "RE": true, "RO": true, "RS": true, "RU": true, "RW": true,
+	"SA": true, "SB": true, "SC": true, "SD": true, "SE": true, "SG": true, "SH": true, "SI": true,
+	"SJ": true, "SK": true, "SL": true, "SM": true, "SN": true, "SO": true, "SR": true, "SS": true,
+	"ST": true, "SV": true, "SX": true, "SY": true, "SZ": true, "TC": true, "TD": true, "TF": true,
+	"TG": true, "TH": true, "TJ": true, "TK": true, "TL": true, "TM": true, "TN": true, "TO": true,
+	"TR": true, "TT": true, "TV": true, "TW": true, "TZ": true, "UA": true, "UG": true, "UM": true,
+	"US": true, "UY": true, "UZ": true, "VA": true, "VC": true, "VE": true, "VG": true, "VI": true,
+	"VN": true, "VU": true, "WF": true, "WS": true, "YE": true, "YT": true, "ZA": true, "ZM": true,
+	"ZW": true,
+}
+
+// checks if rule is a required rule scoped to a method
+func isMethodRequiredRule(rule string) bool {
+	return rule == string("required_"+create) || rule == string("required_"+update) ||
+		rule == string("required_"+validate)
+}
+
+// checks if rule is a default rule (optionally scoped to a method)
+func isDefaultRule(rule string) bool {
+	name, _, _ := strings.Cut(rule, "=")
+	return name == "default" || name == string("default_"+create) ||
+		name == string("default_"+update) || name == string("default_"+validate)
+}
+
+// a rule which is translated to a firestore field transform
+type fieldTransform string
+
+const (
+	increment       fieldTransform = "increment"
+	arrayUnion      fieldTransform = "arrayUnion"
+	arrayRemove     fieldTransform = "arrayRemove"
+	serverTimestamp fieldTransform = "serverTimestamp"
+)
+
+// checks if rule is a field transform rule (optionally scoped to a method)
+func isFieldTransformRule(rule string) bool {
+	transform, scope, scoped := strings.Cut(rule, "_")
+	if scoped && scope != string(create) && scope != string(update) {
+		return false
+	}
+
+	switch fieldTransform(transform) {
+	case increment, arrayUnion, arrayRemove, serverTimestamp:
+		return true
+	}
+
+	return false
+}
+
+// validates if field's type is supported by the field transform
+func validateFieldTransformType(transform fieldTransform, fieldType reflect.Type, fieldPath string) error {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	switch transform {
+	case increment:
+		if isIncrementable(fieldType) {
+			return nil
+		}
+
+		return errors.New("firevault: increment requires a numeric field (excluding uint and uint64) - " + fieldPath)
+	case arrayUnion, arrayRemove:
+		if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array {
+			return nil
+		}
+
+		return errors.New("firevault: " + string(transform) + " requires a slice or array field - " + fieldPath)
+	case serverTimestamp:
+		if fieldType == reflect.TypeOf(time.Time{}) {
+			return nil
+		}
+
+		return errors.New("firevault: serverTimestamp requires a time.Time field - " + fieldPath)
+	}
+
+	return errors.New("firevault: unknown field transform - " + fieldPath)
+}
+
+// checks if type is an integer or float
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// checks if type is a number which firestore can increment by
+// (i.e. not a uint or uint64, which it can't store)
+func isIncrementable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return false
+	}
+
+	return isNumeric(t)
 }
 
 // validates if field is of supported type
@@ -45,7 +235,6 @@ func validateRequired(_ context.Context, _ string, fieldValue reflect.Value, _ s
 
 // validates if field is a valid email address
 func validateEmail(_ context.Context, _ string, fieldValue reflect.Value, _ string) (bool, error) {
-	emailRegex := regexp.MustCompile("^(?:(?:(?:(?:[a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+(?:\\.([a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+)*)|(?:(?:\\x22)(?:(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(?:\\x20|\\x09)+)?(?:(?:[\\x01-\\x08\\x0b\\x0c\\x0e-\\x1f\\x7f]|\\x21|[\\x23-\\x5b]|[\\x5d-\\x7e]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[\\x01-\\x09\\x0b\\x0c\\x0d-\\x7f]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}]))))*(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(\\x20|\\x09)+)?(?:\\x22))))@(?:(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.)+(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.?$")
 	return emailRegex.MatchString(fieldValue.String()), nil
 }
 
@@ -164,3 +353,533 @@ func validateMin(
 
 	return false, errors.New("firevault: invalid field type - " + fieldPath)
 }
+
+// compare field's value, or length, to param's value, returning
+// -1, 0 or +1 if it's less than, equal to or greater than it
+func compareToParam(fieldPath string, fieldValue reflect.Value, param string) (int, error) {
+	switch fieldValue.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		i, err := asInt(param)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(int64(fieldValue.Len()), i), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := asInt(param)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(fieldValue.Int(), i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := asUint(param)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(fieldValue.Uint(), u), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := asFloat(param)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(fieldValue.Float(), f), nil
+	case reflect.Struct:
+		timeType := reflect.TypeOf(time.Time{})
+
+		if fieldValue.Type().ConvertibleTo(timeType) {
+			p, err := asTime(param)
+			if err != nil {
+				return 0, err
+			}
+
+			t := fieldValue.Convert(timeType).Interface().(time.Time)
+
+			return t.Compare(p), nil
+		}
+	}
+
+	return 0, errors.New("firevault: invalid field type - " + fieldPath)
+}
+
+// get the field's string value, or error if it's not a string
+func asString(fieldPath string, fieldValue reflect.Value) (string, error) {
+	if fieldValue.Kind() != reflect.String {
+		return "", errors.New("firevault: invalid field type - " + fieldPath)
+	}
+
+	return fieldValue.String(), nil
+}
+
+// validates if field's length (or number value) is equal to param's value
+func validateLen(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	if param == "" {
+		return false, errors.New("firevault: provide a len param - " + fieldPath)
+	}
+
+	c, err := compareToParam(fieldPath, fieldValue, param)
+	return c == 0, err
+}
+
+// validates if field's value is equal to param's value (for strings and
+// booleans the value is compared, for slices and maps the length)
+func validateEq(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	switch fieldValue.Kind() {
+	case reflect.String:
+		return fieldValue.String() == param, nil
+	case reflect.Bool:
+		b, err := asBool(param)
+		if err != nil {
+			return false, err
+		}
+
+		return fieldValue.Bool() == b, nil
+	}
+
+	if param == "" {
+		return false, errors.New("firevault: provide an eq param - " + fieldPath)
+	}
+
+	c, err := compareToParam(fieldPath, fieldValue, param)
+	return c == 0, err
+}
+
+// validates if field's value is not equal to param's value
+func validateNe(ctx context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	eq, err := validateEq(ctx, fieldPath, fieldValue, param)
+	return !eq, err
+}
+
+// validates if field's value, or length, is greater than param's value
+func validateGt(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	if param == "" {
+		return false, errors.New("firevault: provide a gt param - " + fieldPath)
+	}
+
+	c, err := compareToParam(fieldPath, fieldValue, param)
+	return c > 0, err
+}
+
+// validates if field's value, or length, is greater than or equal to param's value
+func validateGte(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	if param == "" {
+		return false, errors.New("firevault: provide a gte param - " + fieldPath)
+	}
+
+	c, err := compareToParam(fieldPath, fieldValue, param)
+	return c >= 0, err
+}
+
+// validates if field's value, or length, is less than param's value
+func validateLt(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	if param == "" {
+		return false, errors.New("firevault: provide a lt param - " + fieldPath)
+	}
+
+	c, err := compareToParam(fieldPath, fieldValue, param)
+	return c < 0, err
+}
+
+// validates if field's value, or length, is less than or equal to param's value
+func validateLte(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	if param == "" {
+		return false, errors.New("firevault: provide a lte param - " + fieldPath)
+	}
+
+	c, err := compareToParam(fieldPath, fieldValue, param)
+	return c <= 0, err
+}
+
+// validates if field's value is one of the space-separated
+// values in param (e.g. oneof=red green blue)
+func validateOneOf(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	var value string
+
+	switch fieldValue.Kind() {
+	case reflect.String:
+		value = fieldValue.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = strconv.FormatInt(fieldValue.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = strconv.FormatUint(fieldValue.Uint(), 10)
+	default:
+		return false, errors.New("firevault: invalid field type - " + fieldPath)
+	}
+
+	for _, option := range strings.Fields(param) {
+		if option == value {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// validates if field is a URL with a scheme and a host
+func validateURL(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return false, nil
+	}
+
+	return u.Scheme != "" && u.Host != "", nil
+}
+
+// validates if field is an absolute URI (or absolute path)
+func validateURI(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = url.ParseRequestURI(s)
+	return err == nil, nil
+}
+
+// validates if field matches the regex
+func matchRegex(regex *regexp.Regexp, fieldPath string, fieldValue reflect.Value) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return regex.MatchString(s), nil
+}
+
+// validates if field is a UUID
+func validateUUID(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	return matchRegex(uuidRegex, fieldPath, fieldValue)
+}
+
+// validates if field only contains ASCII letters
+func validateAlpha(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	return matchRegex(alphaRegex, fieldPath, fieldValue)
+}
+
+// validates if field only contains ASCII letters and digits
+func validateAlphanum(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	return matchRegex(alphanumRegex, fieldPath, fieldValue)
+}
+
+// validates if field is a number, or a string holding a number
+func validateNumeric(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	if isNumeric(fieldValue.Type()) {
+		return true, nil
+	}
+
+	return matchRegex(numericRegex, fieldPath, fieldValue)
+}
+
+// validates if field is a hex color (e.g. #fff or #ffffff)
+func validateHexColor(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	return matchRegex(hexColorRegex, fieldPath, fieldValue)
+}
+
+// validates if field is an E.164 phone number (e.g. +447123456789)
+func validateE164(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	return matchRegex(e164Regex, fieldPath, fieldValue)
+}
+
+// validates if field is an ISO 3166-1 alpha-2 country code
+func validateISO3166(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return iso3166Codes[s], nil
+}
+
+// validates if field is an IPv4 or IPv6 address
+func validateIP(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return net.ParseIP(s) != nil, nil
+}
+
+// validates if field is an IPv4 or IPv6 CIDR block
+func validateCIDR(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	_, _, err = net.ParseCIDR(s)
+	return err == nil, nil
+}
+
+// validates if field contains param's value
+func validateContains(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(s, param), nil
+}
+
+// validates if field starts with param's value
+func validateStartsWith(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.HasPrefix(s, param), nil
+}
+
+// validates if field ends with param's value
+func validateEndsWith(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.HasSuffix(s, param), nil
+}
+
+// validates if the slice's (or array's) elements, or the map's
+// values, are unique
+func validateUnique(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	var values []reflect.Value
+
+	switch fieldValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fieldValue.Len(); i++ {
+			values = append(values, fieldValue.Index(i))
+		}
+	case reflect.Map:
+		iter := fieldValue.MapRange()
+		for iter.Next() {
+			values = append(values, iter.Value())
+		}
+	default:
+		return false, errors.New("firevault: invalid field type - " + fieldPath)
+	}
+
+	for i := range values {
+		for j := i + 1; j < len(values); j++ {
+			if reflect.DeepEqual(values[i].Interface(), values[j].Interface()) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// validates if field is valid JSON
+func validateJSON(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	return json.Valid([]byte(s)), nil
+}
+
+// validates if field is a (standard, padded) base64 encoded string
+func validateBase64(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (bool, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = base64.StdEncoding.DecodeString(s)
+	return err == nil, nil
+}
+
+// apply the function to the field's string value, keeping the field's type
+func transformString(fieldPath string, fieldValue reflect.Value, fn func(string) string) (interface{}, error) {
+	s, err := asString(fieldPath, fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return reflect.ValueOf(fn(s)).Convert(fieldValue.Type()).Interface(), nil
+}
+
+// removes leading and trailing white space
+func transformTrim(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, strings.TrimSpace)
+}
+
+// converts all letters to lower case
+func transformLowercase(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, strings.ToLower)
+}
+
+// converts all letters to upper case
+func transformUppercase(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, strings.ToUpper)
+}
+
+// converts the first letter of each word to upper case,
+// and the rest to lower case
+func transformTitle(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, cases.Title(language.Und).String)
+}
+
+// converts to a lower case, URL friendly slug (e.g. "Crème Brûlée!" to "creme-brulee")
+func transformSlugify(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, func(s string) string {
+		var b strings.Builder
+
+		// decompose characters, so accents can be dropped
+		for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+			switch {
+			case unicode.Is(unicode.Mn, r):
+			case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+				b.WriteRune(r)
+			case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+				b.WriteRune('-')
+			}
+		}
+
+		return strings.TrimSuffix(b.String(), "-")
+	})
+}
+
+// removes surrounding white space and converts to lower case
+func transformNormalizeEmail(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, func(s string) string {
+		return strings.ToLower(strings.TrimSpace(s))
+	})
+}
+
+// removes formatting characters (e.g. spaces, dashes and brackets) from a
+// phone number, and replaces a leading international "00" prefix with "+"
+func transformNormalizePhoneE164(
+	_ context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	_ string,
+) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, func(s string) string {
+		s = strings.TrimSpace(s)
+
+		// drop the national trunk prefix after a country code (e.g. +44 (0)20)
+		if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "00") {
+			s = strings.Replace(s, "(0)", "", 1)
+		}
+
+		var b strings.Builder
+
+		for i, r := range s {
+			if unicode.IsDigit(r) || (r == '+' && i == 0) {
+				b.WriteRune(r)
+			}
+		}
+
+		phone := b.String()
+		if strings.HasPrefix(phone, "00") {
+			phone = "+" + phone[2:]
+		}
+
+		return phone
+	})
+}
+
+// converts to the Unicode NFC normal form
+func transformUnicodeNFC(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	return transformString(fieldPath, fieldValue, norm.NFC.String)
+}
+
+// shortens to at most param's number of characters (e.g. truncate=10)
+func transformTruncate(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (interface{}, error) {
+	if param == "" {
+		return nil, errors.New("firevault: provide a truncate param - " + fieldPath)
+	}
+
+	n, err := asInt(param)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		return nil, errors.New("firevault: truncate param must not be negative - " + fieldPath)
+	}
+
+	return transformString(fieldPath, fieldValue, func(s string) string {
+		runes := []rune(s)
+		if int64(len(runes)) <= n {
+			return s
+		}
+
+		return string(runes[:n])
+	})
+}
+
+// rounds a float to param's number of decimal places (or to
+// a whole number, if no param is provided)
+func transformRound(_ context.Context, fieldPath string, fieldValue reflect.Value, param string) (interface{}, error) {
+	if fieldValue.Kind() != reflect.Float32 && fieldValue.Kind() != reflect.Float64 {
+		return nil, errors.New("firevault: invalid field type - " + fieldPath)
+	}
+
+	var places int64
+
+	if param != "" {
+		var err error
+
+		places, err = asInt(param)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pow := math.Pow(10, float64(places))
+	rounded := math.Round(fieldValue.Float()*pow) / pow
+
+	return reflect.ValueOf(rounded).Convert(fieldValue.Type()).Interface(), nil
+}
+
+// converts a time to UTC
+func transformUTC(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	timeType := reflect.TypeOf(time.Time{})
+
+	if fieldValue.Kind() != reflect.Struct || !fieldValue.Type().ConvertibleTo(timeType) {
+		return nil, errors.New("firevault: invalid field type - " + fieldPath)
+	}
+
+	t := fieldValue.Convert(timeType).Interface().(time.Time)
+
+	return reflect.ValueOf(t.UTC()).Convert(fieldValue.Type()).Interface(), nil
+}
+
+// removes repeated elements from a slice, keeping the first occurrence
+func transformDedupe(_ context.Context, fieldPath string, fieldValue reflect.Value, _ string) (interface{}, error) {
+	if fieldValue.Kind() != reflect.Slice {
+		return nil, errors.New("firevault: invalid field type - " + fieldPath)
+	}
+
+	deduped := reflect.MakeSlice(fieldValue.Type(), 0, fieldValue.Len())
+
+	for i := 0; i < fieldValue.Len(); i++ {
+		elem := fieldValue.Index(i)
+		seen := false
+
+		for j := 0; j < deduped.Len(); j++ {
+			if reflect.DeepEqual(deduped.Index(j).Interface(), elem.Interface()) {
+				seen = true
+				break
+			}
+		}
+
+		if !seen {
+			deduped = reflect.Append(deduped, elem)
+		}
+	}
+
+	return deduped.Interface(), nil
+}
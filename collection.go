@@ -3,12 +3,12 @@ package firevault
 import (
 	"context"
 	"errors"
+	"reflect"
+	"slices"
 	"strings"
-	"sync"
+	"time"
 
 	"cloud.google.com/go/firestore"
-	"cloud.google.com/go/firestore/apiv1/firestorepb"
-	"google.golang.org/api/iterator"
 )
 
 // A Firevault CollectionRef holds a reference to a
@@ -17,13 +17,112 @@ import (
 type CollectionRef[T interface{}] struct {
 	connection *Connection
 	ref        *firestore.CollectionRef
+	query      firestore.Query
 }
 
-// A Firevault Document holds the ID and data related to
-// fetched document.
+// A Firevault Document holds the ID, data and metadata
+// related to fetched document.
 type Document[T interface{}] struct {
-	ID   string
+	// ID is the document's ID.
+	ID string
+	// Data is the document's data, decoded into T.
 	Data T
+	// Path is the document's full path, relative to the
+	// database root (e.g. "users/abc/orders/xyz").
+	Path string
+	// ParentPath is the full path of the collection which
+	// holds the document, relative to the database root
+	// (e.g. "users/abc/orders").
+	ParentPath string
+	// CreateTime is the time at which the document was created.
+	CreateTime time.Time
+	// UpdateTime is the time at which the document was last
+	// changed.
+	UpdateTime time.Time
+	// ReadTime is the time at which the document was read.
+	ReadTime time.Time
+	// the fetched snapshot, holding the fields which were loaded
+	snapshot *firestore.DocumentSnapshot
+}
+
+// A DocumentVersion identifies a specific version of a document,
+// by its path (or ID) and last update time. It is implemented by
+// Document, regardless of its type parameter.
+type DocumentVersion interface {
+	version() (string, string, time.Time)
+}
+
+// get the document's ID, path and last update time
+func (d Document[T]) version() (string, string, time.Time) {
+	return d.ID, d.Path, d.UpdateTime
+}
+
+// a document which can be used as a query cursor
+type cursorDocument interface {
+	cursor(v *validator, docRef cursorRefFn, orders []order) ([]interface{}, error)
+}
+
+// gets the reference of a cursor document, from its ID and path
+type cursorRefFn func(id string, path string) (*firestore.DocumentRef, error)
+
+// get the cursor values for the orders - the document's snapshot
+// if it was fetched, otherwise its reference and data values
+func (d Document[T]) cursor(v *validator, docRef cursorRefFn, orders []order) ([]interface{}, error) {
+	if d.snapshot != nil {
+		return []interface{}{d.snapshot}, nil
+	}
+
+	if len(orders) == 0 {
+		return nil, errors.New("firevault: cursor document was not fetched, so query must have orders")
+	}
+
+	values := make([]interface{}, 0, len(orders))
+
+	for _, order := range orders {
+		if order.path == DocumentID {
+			if d.ID == "" && d.Path == "" {
+				return nil, errors.New("firevault: cursor document has no ID")
+			}
+
+			ref, err := docRef(d.ID, d.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, ref)
+			continue
+		}
+
+		value, err := v.getFieldValue(reflect.ValueOf(d.Data), order.path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !value.CanInterface() {
+			return nil, errors.New("firevault: unexported field - " + order.path)
+		}
+
+		values = append(values, value.Interface())
+	}
+
+	return values, nil
+}
+
+// Loaded reports whether the field at the path (using
+// dot-separation) was returned by Firestore when the document
+// was fetched.
+//
+// It can be used to tell apart unselected fields from fields
+// set to their zero value, when using Query's Select method.
+// Fields which are missing from the stored document are never
+// loaded.
+func (d Document[T]) Loaded(path string) bool {
+	if d.snapshot == nil {
+		return false
+	}
+
+	_, err := d.snapshot.DataAtPath(strings.Split(path, "."))
+	return err == nil
 }
 
 // Create a new CollectionRef instance.
@@ -47,7 +146,65 @@ func Collection[T interface{}](connection *Connection, path string) *CollectionR
 		return nil
 	}
 
-	return &CollectionRef[T]{connection, collectionRef}
+	return &CollectionRef[T]{connection, collectionRef, collectionRef.Query}
+}
+
+// Create a new CollectionRef instance, for the subcollection
+// with the provided name, of the parent collection's document
+// with the provided ID.
+//
+// Returns an error if the ID or name is empty or contains
+// a slash.
+func SubCollection[U interface{}, T interface{}](
+	parent *CollectionRef[T],
+	docID string,
+	name string,
+) (*CollectionRef[U], error) {
+	if parent == nil || parent.ref == nil {
+		return nil, errors.New("firevault: nil CollectionRef")
+	}
+
+	if docID == "" || strings.Contains(docID, "/") {
+		return nil, errors.New("firevault: invalid document ID - " + docID)
+	}
+
+	return subCollection[U](parent.connection, relativePath(parent.ref.Path)+"/"+docID, name)
+}
+
+// Create a new CollectionRef instance, for the subcollection
+// with the provided name, of the fetched document.
+//
+// Returns an error if the document has no path (i.e. it
+// wasn't fetched), or the name is empty or contains a slash.
+func SubCollectionOf[U interface{}, T interface{}](
+	connection *Connection,
+	doc Document[T],
+	name string,
+) (*CollectionRef[U], error) {
+	if doc.Path == "" {
+		return nil, errors.New("firevault: document has no path")
+	}
+
+	return subCollection[U](connection, doc.Path, name)
+}
+
+// create a CollectionRef for the named subcollection of the document at the path
+func subCollection[U interface{}](connection *Connection, docPath string, name string) (*CollectionRef[U], error) {
+	if connection == nil || connection.client == nil {
+		return nil, errors.New("firevault: nil Connection or Firestore Client")
+	}
+
+	path, err := subCollectionPath(docPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionRef := connection.client.Collection(path)
+	if collectionRef == nil {
+		return nil, errors.New("firevault: invalid collection path - " + path)
+	}
+
+	return &CollectionRef[U]{connection, collectionRef, collectionRef.Query}, nil
 }
 
 // Validate and transform provided data.
@@ -63,6 +220,11 @@ func (c *CollectionRef[T]) Validate(ctx context.Context, data *T, opts ...Option
 }
 
 // Create a Firestore document with provided data (after validation).
+//
+// If a custom ID is specified (using Options), and a document
+// with that ID already exists, ErrAlreadyExists is returned
+// (rather than the document being overwritten, as it was by
+// earlier versions).
 func (c *CollectionRef[T]) Create(ctx context.Context, data *T, opts ...Options) (string, error) {
 	if c == nil {
 		return "", errors.New("firevault: nil CollectionRef")
@@ -78,14 +240,14 @@ func (c *CollectionRef[T]) Create(ctx context.Context, data *T, opts ...Options)
 	if id == "" {
 		docRef, _, err := c.ref.Add(ctx, dataMap)
 		if err != nil {
-			return "", err
+			return "", wrapError(err)
 		}
 
 		id = docRef.ID
 	} else {
-		_, err = c.ref.Doc(id).Set(ctx, dataMap)
+		_, err = c.ref.Doc(id).Create(ctx, dataMap)
 		if err != nil {
-			return "", err
+			return "", docError(wrapError(err), id)
 		}
 	}
 
@@ -94,76 +256,90 @@ func (c *CollectionRef[T]) Create(ctx context.Context, data *T, opts ...Options)
 
 // Update all Firestore documents which match provided Query
 // (after data validation). The operation is not atomic.
+//
+// Only existing documents are updated - missing documents are
+// not created (as they were by earlier versions, which merged
+// the data into them). If the Query specifies IDs of documents
+// which do not exist, an error wrapping ErrNotFound is returned
+// for each of them.
+//
+// If documents have changed since the version specified with
+// the IfUnchangedSince or IfMatch options, a *ConflictError
+// listing them is returned.
 func (c *CollectionRef[T]) Update(ctx context.Context, query Query, data *T, opts ...Options) error {
 	if c == nil {
 		return errors.New("firevault: nil CollectionRef")
 	}
 
-	valOptions, _, mergeFields := c.parseOptions(update, opts...)
-
-	dataMap, err := c.connection.validator.validate(ctx, data, valOptions)
+	updates, err := c.prepareUpdates(ctx, data, opts...)
 	if err != nil {
 		return err
 	}
 
-	if len(opts) > 0 {
-		// delete all mergeFields which are empty (i.e. not present in dataMap)
-		c.deleteEmptyMergeFields(dataMap, opts[0].mergeFields)
+	if len(updates) == 0 {
+		return nil
 	}
 
-	return c.bulkOperation(ctx, query, func(bw *firestore.BulkWriter, docID string) error {
-		_, err := bw.Set(c.ref.Doc(docID), dataMap, mergeFields)
-		return err
-	})
+	return c.bulkOperation(
+		ctx,
+		query,
+		opts,
+		func(
+			bw *firestore.BulkWriter,
+			docRef *firestore.DocumentRef,
+			preconds []firestore.Precondition,
+		) (*firestore.BulkWriterJob, error) {
+			return bw.Update(docRef, updates, preconds...)
+		},
+	)
 }
 
 // Delete all Firestore documents which match provided Query.
 // The operation is not atomic.
-func (c *CollectionRef[T]) Delete(ctx context.Context, query Query) error {
+//
+// If documents have changed since the version specified with
+// the IfUnchangedSince or IfMatch options, a *ConflictError
+// listing them is returned.
+func (c *CollectionRef[T]) Delete(ctx context.Context, query Query, opts ...Options) error {
 	if c == nil {
 		return errors.New("firevault: nil CollectionRef")
 	}
 
-	return c.bulkOperation(ctx, query, func(bw *firestore.BulkWriter, docID string) error {
-		_, err := bw.Delete(c.ref.Doc(docID))
-		return err
-	})
+	return c.bulkOperation(
+		ctx,
+		query,
+		opts,
+		func(
+			bw *firestore.BulkWriter,
+			docRef *firestore.DocumentRef,
+			preconds []firestore.Precondition,
+		) (*firestore.BulkWriterJob, error) {
+			return bw.Delete(docRef, preconds...)
+		},
+	)
 }
 
 // Find all Firestore documents which match provided Query.
+//
+// If the Query specifies IDs of documents which do not exist,
+// an error wrapping ErrNotFound is returned.
 func (c *CollectionRef[T]) Find(ctx context.Context, query Query) ([]Document[T], error) {
 	if c == nil {
 		return nil, errors.New("firevault: nil CollectionRef")
 	}
 
-	if len(query.ids) > 0 {
-		return c.fetchDocsByID(ctx, query.ids)
-	}
-
-	return c.fetchDocsByQuery(ctx, query)
+	return c.find(ctx, query, nil)
 }
 
 // Find the first Firestore document which matches provided Query.
+//
+// If no document matches the Query, ErrNotFound is returned.
 func (c *CollectionRef[T]) FindOne(ctx context.Context, query Query) (Document[T], error) {
 	if c == nil {
 		return Document[T]{}, errors.New("firevault: nil CollectionRef")
 	}
 
-	if len(query.ids) > 0 {
-		docs, err := c.fetchDocsByID(ctx, query.ids[0:1])
-		if err != nil {
-			return Document[T]{}, err
-		}
-
-		return docs[0], nil
-	}
-
-	docs, err := c.fetchDocsByQuery(ctx, query.Limit(1))
-	if err != nil {
-		return Document[T]{}, err
-	}
-
-	return docs[0], nil
+	return c.findOne(ctx, query, nil)
 }
 
 // Find number of Firestore documents which match provided Query.
@@ -176,28 +352,19 @@ func (c *CollectionRef[T]) Count(ctx context.Context, query Query) (int64, error
 		return int64(len(query.ids)), nil
 	}
 
-	builtQuery := c.buildQuery(query)
-	results, err := builtQuery.NewAggregationQuery().WithCount("all").Get(ctx)
+	results, err := c.aggregate(ctx, query, AggregateCount("all"))
 	if err != nil {
 		return 0, err
 	}
 
-	count, ok := results["all"]
-	if !ok {
-		return 0, errors.New("firestore: couldn't get alias for COUNT from results")
-	}
-
-	countValue := count.(*firestorepb.Value)
-	countInt := countValue.GetIntegerValue()
-
-	return countInt, nil
+	return results["all"].Int(), nil
 }
 
 // extract passed options
 func (c *CollectionRef[T]) parseOptions(
 	method methodType,
 	opts ...Options,
-) (validationOpts, string, firestore.SetOption) {
+) (validationOpts, string, []firestore.FieldPath) {
 	options := validationOpts{
 		method:             method,
 		skipValidation:     false,
@@ -205,7 +372,7 @@ func (c *CollectionRef[T]) parseOptions(
 	}
 
 	if len(opts) == 0 {
-		return options, "", firestore.MergeAll
+		return options, "", nil
 	}
 
 	// parse options
@@ -215,6 +382,10 @@ func (c *CollectionRef[T]) parseOptions(
 		options.skipValidation = true
 	}
 
+	if passedOpts.collectAllErrors {
+		options.collectAllErrors = true
+	}
+
 	if len(passedOpts.allowEmptyFields) > 0 {
 		options.emptyFieldsAllowed = passedOpts.allowEmptyFields
 	}
@@ -227,10 +398,85 @@ func (c *CollectionRef[T]) parseOptions(
 			fps = append(fps, fp)
 		}
 
-		return options, passedOpts.id, firestore.Merge(fps...)
+		return options, passedOpts.id, fps
+	}
+
+	return options, passedOpts.id, nil
+}
+
+// validate data and build the list of field updates,
+// based on passed options
+func (c *CollectionRef[T]) prepareUpdates(
+	ctx context.Context,
+	data *T,
+	opts ...Options,
+) ([]firestore.Update, error) {
+	valOptions, _, mergeFields := c.parseOptions(update, opts...)
+
+	dataMap, err := c.connection.validator.validate(ctx, data, valOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts) == 0 {
+		return c.buildUpdates(dataMap, mergeFields), nil
+	}
+
+	// delete all mergeFields which are empty (i.e. not present in dataMap)
+	c.deleteEmptyMergeFields(dataMap, opts[0].mergeFields)
+
+	updates := c.buildUpdates(dataMap, mergeFields)
+
+	// add field transforms, replacing any updates of the same fields
+	for _, t := range opts[0].transforms {
+		err := c.connection.validator.validateTransformValue(
+			reflect.TypeFor[T](),
+			t.path,
+			t.transform,
+			t.value,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		fp := firestore.FieldPath(strings.Split(t.path, "."))
+
+		updates = slices.DeleteFunc(updates, func(u firestore.Update) bool {
+			return slices.Equal(u.FieldPath, fp)
+		})
+
+		updates = append(updates, firestore.Update{
+			FieldPath: fp,
+			Value:     c.connection.validator.applyFieldTransform(t.transform, t.value),
+		})
 	}
 
-	return options, passedOpts.id, firestore.MergeAll
+	return updates, nil
+}
+
+// get the last update time precondition to apply when writing
+// the document, based on passed options
+func (c *CollectionRef[T]) preconditions(
+	docRef *firestore.DocumentRef,
+	opts ...Options,
+) []firestore.Precondition {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	for _, v := range opts[0].versions {
+		// match by path when known, as IDs are not unique across collections
+		id, path, updateTime := v.version()
+		if (path != "" && path == relativePath(docRef.Path)) || (path == "" && id == docRef.ID) {
+			return []firestore.Precondition{firestore.LastUpdateTime(updateTime)}
+		}
+	}
+
+	if !opts[0].unchangedSince.IsZero() {
+		return []firestore.Precondition{firestore.LastUpdateTime(opts[0].unchangedSince)}
+	}
+
+	return nil
 }
 
 // delete any fields which are not present in map and are specified in mergeFields opt
@@ -279,12 +525,75 @@ func (c *CollectionRef[T]) deleteEmptyMergeFields(
 	}
 }
 
+// build the list of field updates from the data map - if no merge
+// fields are specified, every (nested) field in the map is updated
+func (c *CollectionRef[T]) buildUpdates(
+	dataMap map[string]interface{},
+	mergeFields []firestore.FieldPath,
+) []firestore.Update {
+	if len(mergeFields) == 0 {
+		return c.flattenUpdates(dataMap, nil, nil)
+	}
+
+	updates := make([]firestore.Update, 0, len(mergeFields))
+
+	for _, fp := range mergeFields {
+		updates = append(updates, firestore.Update{FieldPath: fp, Value: c.valueAtPath(dataMap, fp)})
+	}
+
+	return updates
+}
+
+// turn a (nested) data map into updates of its leaf fields
+func (c *CollectionRef[T]) flattenUpdates(
+	dataMap map[string]interface{},
+	path firestore.FieldPath,
+	updates []firestore.Update,
+) []firestore.Update {
+	for field, value := range dataMap {
+		fp := append(path[:len(path):len(path)], field)
+
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			updates = c.flattenUpdates(m, fp, updates)
+			continue
+		}
+
+		updates = append(updates, firestore.Update{FieldPath: fp, Value: value})
+	}
+
+	return updates
+}
+
+// get the value at the field path from the data map, or the
+// delete sentinel if it's not present
+func (c *CollectionRef[T]) valueAtPath(dataMap map[string]interface{}, fp firestore.FieldPath) interface{} {
+	var current interface{} = dataMap
+
+	for _, field := range fp {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return firestore.Delete
+		}
+
+		current, ok = m[field]
+		if !ok {
+			return firestore.Delete
+		}
+	}
+
+	return current
+}
+
 // build a new firestore query
-func (c *CollectionRef[T]) buildQuery(query Query) firestore.Query {
-	newQuery := c.ref.Query
+func (c *CollectionRef[T]) buildQuery(query Query) (firestore.Query, error) {
+	newQuery := c.query
 
 	for _, filter := range query.filters {
-		newQuery = newQuery.Where(filter.path, filter.operator, filter.value)
+		newQuery = newQuery.WhereEntity(filter.entityFilter())
+	}
+
+	if query.selects != nil {
+		newQuery = newQuery.Select(query.selects...)
 	}
 
 	for _, order := range query.orders {
@@ -292,19 +601,39 @@ func (c *CollectionRef[T]) buildQuery(query Query) firestore.Query {
 	}
 
 	if len(query.startAt) > 0 {
-		newQuery = newQuery.StartAt(query.startAt...)
+		values, err := c.cursorValues(query.startAt, query.orders)
+		if err != nil {
+			return firestore.Query{}, err
+		}
+
+		newQuery = newQuery.StartAt(values...)
 	}
 
 	if len(query.startAfter) > 0 {
-		newQuery = newQuery.StartAfter(query.startAfter...)
+		values, err := c.cursorValues(query.startAfter, query.orders)
+		if err != nil {
+			return firestore.Query{}, err
+		}
+
+		newQuery = newQuery.StartAfter(values...)
 	}
 
 	if len(query.endBefore) > 0 {
-		newQuery = newQuery.EndBefore(query.endBefore...)
+		values, err := c.cursorValues(query.endBefore, query.orders)
+		if err != nil {
+			return firestore.Query{}, err
+		}
+
+		newQuery = newQuery.EndBefore(values...)
 	}
 
 	if len(query.endAt) > 0 {
-		newQuery = newQuery.EndAt(query.endAt...)
+		values, err := c.cursorValues(query.endAt, query.orders)
+		if err != nil {
+			return firestore.Query{}, err
+		}
+
+		newQuery = newQuery.EndAt(values...)
 	}
 
 	if query.limit > 0 {
@@ -319,111 +648,270 @@ func (c *CollectionRef[T]) buildQuery(query Query) firestore.Query {
 		newQuery = newQuery.Offset(query.offset)
 	}
 
-	return newQuery
+	return newQuery, nil
+}
+
+// get the values of a query cursor, replacing a document
+// with its snapshot (or its values for the orders)
+func (c *CollectionRef[T]) cursorValues(values []interface{}, orders []order) ([]interface{}, error) {
+	if len(values) != 1 {
+		return values, nil
+	}
+
+	doc, ok := values[0].(cursorDocument)
+	if !ok {
+		return values, nil
+	}
+
+	return doc.cursor(c.connection.validator, c.cursorRef, orders)
+}
+
+// get the reference of a cursor document, by its path if known (as
+// IDs are not unique across collection groups), or by its ID
+func (c *CollectionRef[T]) cursorRef(id string, path string) (*firestore.DocumentRef, error) {
+	if path != "" {
+		docRef := c.connection.client.Doc(path)
+		if docRef == nil {
+			return nil, errors.New("firevault: invalid cursor document path - " + path)
+		}
+
+		return docRef, nil
+	}
+
+	if c.ref == nil {
+		return nil, errors.New("firevault: cursor document has no path, which collection groups require")
+	}
+
+	return c.ref.Doc(id), nil
+}
+
+// build the query, filtering by document ID if the query specifies
+// IDs (for operations which can't fetch documents directly)
+func (c *CollectionRef[T]) filterQuery(query Query) (firestore.Query, error) {
+	if len(query.ids) == 0 {
+		return c.buildQuery(query)
+	}
+
+	docRefs := make([]*firestore.DocumentRef, 0, len(query.ids))
+
+	for _, docID := range query.ids {
+		docRefs = append(docRefs, c.ref.Doc(docID))
+	}
+
+	return c.ref.Query.Where(firestore.DocumentID, "in", docRefs), nil
 }
 
 // perform a bulk operation
 func (c *CollectionRef[T]) bulkOperation(
 	ctx context.Context,
 	query Query,
-	operation func(*firestore.BulkWriter, string) error,
+	opts []Options,
+	operation func(
+		*firestore.BulkWriter,
+		*firestore.DocumentRef,
+		[]firestore.Precondition,
+	) (*firestore.BulkWriterJob, error),
 ) error {
 	bulkWriter := c.connection.client.BulkWriter(ctx)
 	defer bulkWriter.End()
 
-	var mu sync.Mutex
 	var errs []error
+	writes := make(bulkWrites)
 
-	docIDs := query.ids
+	err := c.forEachRef(ctx, query, nil, func(docRef *firestore.DocumentRef) error {
+		preconds := c.preconditions(docRef, opts...)
 
-	if len(docIDs) == 0 {
-		docs, err := c.Find(ctx, query)
+		job, err := operation(bulkWriter, docRef, preconds)
 		if err != nil {
-			return err
-		}
-
-		for _, doc := range docs {
-			docIDs = append(docIDs, doc.ID)
+			errs = append(errs, c.refError(err, docRef))
+			return nil
 		}
-	}
 
-	for _, docID := range docIDs {
-		err := operation(bulkWriter, docID)
-		if err != nil {
-			mu.Lock()
-			errs = append(errs, errors.New(err.Error()+" (docID: "+docID+")"))
-			mu.Unlock()
-		}
+		writes.add(docRef, job, len(preconds) > 0)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// wait for all operations to complete
 	bulkWriter.Flush()
 
-	return errors.Join(errs...)
+	for _, write := range writes {
+		_, write.err = write.job.Results()
+	}
+
+	return c.bulkError(errs, writes)
 }
 
-// fetch documents based on provided ids
-func (c *CollectionRef[T]) fetchDocsByID(ctx context.Context, ids []string) ([]Document[T], error) {
-	const batchSize = 100
-	var docRefs []*firestore.DocumentRef
-	var docs []Document[T]
+// a single document's write, as part of a bulk operation
+type bulkWrite struct {
+	docRef      *firestore.DocumentRef
+	job         *firestore.BulkWriterJob
+	conditional bool
+	err         error
+}
 
-	for _, docID := range ids {
-		docRefs = append(docRefs, c.ref.Doc(docID))
-	}
+// the writes of a bulk operation, by document path
+// (as IDs are not unique across collection groups)
+type bulkWrites map[string]*bulkWrite
 
-	for i := 0; i < len(docRefs); i += batchSize {
-		end := i + batchSize
-		if end > len(docRefs) {
-			end = len(docRefs)
+// add the document's write
+func (w bulkWrites) add(docRef *firestore.DocumentRef, job *firestore.BulkWriterJob, conditional bool) {
+	w[docRef.Path] = &bulkWrite{docRef: docRef, job: job, conditional: conditional}
+}
+
+// join the errors of a bulk operation, reporting the documents
+// which failed their preconditions in a single ConflictError
+func (c *CollectionRef[T]) bulkError(errs []error, writes bulkWrites) error {
+	var conflicts []string
+
+	for _, write := range writes {
+		if write.err == nil {
+			continue
 		}
 
-		batchRefs := docRefs[i:end]
-		snapshots, err := c.connection.client.GetAll(ctx, batchRefs)
-		if err != nil {
-			return nil, err
+		err := wrapError(write.err)
+
+		if write.conditional && errors.Is(err, ErrFailedPrecondition) {
+			conflicts = append(conflicts, c.refKey(write.docRef))
+			continue
 		}
 
-		for _, docSnap := range snapshots {
-			var doc T
+		errs = append(errs, c.refError(err, write.docRef))
+	}
+
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		errs = append(errs, &ConflictError{conflicts})
+	}
 
-			err = docSnap.DataTo(&doc)
+	return errors.Join(errs...)
+}
+
+// get the key identifying the document in errors - its ID, or
+// its path for collection groups (as IDs are not unique across them)
+func (c *CollectionRef[T]) refKey(docRef *firestore.DocumentRef) string {
+	if c.ref == nil {
+		return relativePath(docRef.Path)
+	}
+
+	return docRef.ID
+}
+
+// add the document's ID to an error, or its path
+// for collection groups
+func (c *CollectionRef[T]) refError(err error, docRef *firestore.DocumentRef) error {
+	if c.ref == nil {
+		return docPathError(err, relativePath(docRef.Path))
+	}
+
+	return docError(err, docRef.ID)
+}
+
+// call fn with the reference of every document which matches the query -
+// documents specified by ID are not fetched, others are streamed
+// from the query, using the transaction if one is provided
+func (c *CollectionRef[T]) forEachRef(
+	ctx context.Context,
+	query Query,
+	tx *firestore.Transaction,
+	fn func(*firestore.DocumentRef) error,
+) error {
+	if len(query.ids) > 0 {
+		for _, docID := range query.ids {
+			err := fn(c.ref.Doc(docID))
 			if err != nil {
-				return nil, err
+				return err
 			}
-
-			docs = append(docs, Document[T]{docSnap.Ref.ID, doc})
 		}
+
+		return nil
 	}
 
-	return docs, nil
+	iter := c.snapshots(ctx, query, tx)
+	defer iter.stop()
+
+	for {
+		docSnap, err := iter.next()
+		if err == Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = fn(docSnap.Ref)
+		if err != nil {
+			return err
+		}
+	}
 }
 
-// fetch documents based on provided Query
-func (c *CollectionRef[T]) fetchDocsByQuery(ctx context.Context, query Query) ([]Document[T], error) {
-	builtQuery := c.buildQuery(query)
-	iter := builtQuery.Documents(ctx)
+// find documents, using the transaction if one is provided
+func (c *CollectionRef[T]) find(
+	ctx context.Context,
+	query Query,
+	tx *firestore.Transaction,
+) ([]Document[T], error) {
+	iter := &DocumentIterator[T]{c, c.snapshots(ctx, query, tx)}
+	defer iter.Stop()
 
 	var docs []Document[T]
 
 	for {
-		docSnap, err := iter.Next()
-		if err == iterator.Done {
-			break
+		doc, err := iter.Next()
+		if err == Done {
+			return docs, nil
 		}
 		if err != nil {
 			return nil, err
 		}
 
-		var doc T
+		docs = append(docs, doc)
+	}
+}
 
-		err = docSnap.DataTo(&doc)
-		if err != nil {
-			return nil, err
-		}
+// find the first document, using the transaction if one is provided
+func (c *CollectionRef[T]) findOne(
+	ctx context.Context,
+	query Query,
+	tx *firestore.Transaction,
+) (Document[T], error) {
+	if len(query.ids) > 0 {
+		query = query.ID(query.ids[0])
+	} else {
+		query = query.Limit(1)
+	}
 
-		docs = append(docs, Document[T]{docSnap.Ref.ID, doc})
+	iter := &DocumentIterator[T]{c, c.snapshots(ctx, query, tx)}
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == Done {
+		return Document[T]{}, ErrNotFound
+	}
+
+	return doc, err
+}
+
+// decode a document snapshot, using firevault tag names for structs
+func (c *CollectionRef[T]) decodeDoc(docSnap *firestore.DocumentSnapshot) (Document[T], error) {
+	var data T
+
+	err := decodeSnapshot(docSnap, &data)
+	if err != nil {
+		return Document[T]{}, err
 	}
 
-	return docs, nil
+	return Document[T]{
+		ID:         docSnap.Ref.ID,
+		Data:       data,
+		Path:       relativePath(docSnap.Ref.Path),
+		ParentPath: relativePath(docSnap.Ref.Parent.Path),
+		CreateTime: docSnap.CreateTime,
+		UpdateTime: docSnap.UpdateTime,
+		ReadTime:   docSnap.ReadTime,
+		snapshot:   docSnap,
+	}, nil
 }
@@ -0,0 +1,183 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// A Firevault CollectionGroupRef holds a reference to all
+// Firestore Collections with the same ID (e.g. the "comments"
+// subcollections of every post), and allows for the fetching,
+// updating (with validation) and deleting of documents in them.
+//
+// Documents can't be created through a CollectionGroupRef, as
+// it doesn't refer to a single collection.
+type CollectionGroupRef[T interface{}] struct {
+	collection *CollectionRef[T]
+}
+
+// Create a new CollectionGroupRef instance.
+//
+// A Firevault CollectionGroupRef holds a reference to all
+// Firestore Collections with the same ID (e.g. the "comments"
+// subcollections of every post), and allows for the fetching,
+// updating (with validation) and deleting of documents in them.
+//
+// The id argument is the collection ID (i.e. the last segment
+// of the collections' paths), and must not contain a slash.
+func CollectionGroup[T interface{}](connection *Connection, id string) *CollectionGroupRef[T] {
+	if connection == nil || connection.client == nil || id == "" || strings.Contains(id, "/") {
+		return nil
+	}
+
+	groupRef := connection.client.CollectionGroup(id)
+
+	return &CollectionGroupRef[T]{&CollectionRef[T]{connection, nil, groupRef.Query}}
+}
+
+// Update all Firestore documents in the collection group
+// which match provided Query (after data validation).
+//
+// The same rules (and Options) as for CollectionRef's Update
+// method apply. The Query must not specify IDs, as they are
+// not unique across collections - errors identify documents
+// by their paths instead.
+func (g *CollectionGroupRef[T]) Update(ctx context.Context, query Query, data *T, opts ...Options) error {
+	if g == nil {
+		return errors.New("firevault: nil CollectionGroupRef")
+	}
+
+	err := checkGroupQuery(query)
+	if err != nil {
+		return err
+	}
+
+	return g.collection.Update(ctx, query, data, opts...)
+}
+
+// Delete all Firestore documents in the collection group
+// which match provided Query.
+//
+// The same rules (and Options) as for CollectionRef's Delete
+// method apply. The Query must not specify IDs, as they are
+// not unique across collections - errors identify documents
+// by their paths instead.
+func (g *CollectionGroupRef[T]) Delete(ctx context.Context, query Query, opts ...Options) error {
+	if g == nil {
+		return errors.New("firevault: nil CollectionGroupRef")
+	}
+
+	err := checkGroupQuery(query)
+	if err != nil {
+		return err
+	}
+
+	return g.collection.Delete(ctx, query, opts...)
+}
+
+// Find all Firestore documents in the collection group which
+// match provided Query.
+//
+// Each Document's ParentPath holds the path of the collection
+// it was found in. The Query must not specify IDs, as they are
+// not unique across collections.
+func (g *CollectionGroupRef[T]) Find(ctx context.Context, query Query) ([]Document[T], error) {
+	if g == nil {
+		return nil, errors.New("firevault: nil CollectionGroupRef")
+	}
+
+	err := checkGroupQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.collection.Find(ctx, query)
+}
+
+// Find the first Firestore document in the collection group
+// which matches provided Query.
+//
+// If no document matches the Query, ErrNotFound is returned.
+// The Query must not specify IDs, as they are not unique
+// across collections.
+func (g *CollectionGroupRef[T]) FindOne(ctx context.Context, query Query) (Document[T], error) {
+	if g == nil {
+		return Document[T]{}, errors.New("firevault: nil CollectionGroupRef")
+	}
+
+	err := checkGroupQuery(query)
+	if err != nil {
+		return Document[T]{}, err
+	}
+
+	return g.collection.FindOne(ctx, query)
+}
+
+// Find number of Firestore documents in the collection group
+// which match provided Query.
+//
+// The Query must not specify IDs, as they are not unique
+// across collections.
+func (g *CollectionGroupRef[T]) Count(ctx context.Context, query Query) (int64, error) {
+	if g == nil {
+		return 0, errors.New("firevault: nil CollectionGroupRef")
+	}
+
+	err := checkGroupQuery(query)
+	if err != nil {
+		return 0, err
+	}
+
+	return g.collection.Count(ctx, query)
+}
+
+// Iter returns a DocumentIterator over all Firestore documents
+// in the collection group which match provided Query.
+//
+// The iterator must be stopped (using Stop), unless Next has
+// returned Done or an error. The Query must not specify IDs,
+// as they are not unique across collections.
+func (g *CollectionGroupRef[T]) Iter(ctx context.Context, query Query) *DocumentIterator[T] {
+	if g == nil {
+		return &DocumentIterator[T]{
+			snapshots: &snapshotIterator{err: errors.New("firevault: nil CollectionGroupRef")},
+		}
+	}
+
+	err := checkGroupQuery(query)
+	if err != nil {
+		return &DocumentIterator[T]{snapshots: &snapshotIterator{err: err}}
+	}
+
+	return g.collection.Iter(ctx, query)
+}
+
+// Page fetches a single page (of up to pageSize documents) of
+// the Firestore documents in the collection group which match
+// provided Query.
+//
+// The same rules as for CollectionRef's Page method apply.
+// The Query must not specify IDs, as they are not unique
+// across collections.
+func (g *CollectionGroupRef[T]) Page(
+	ctx context.Context,
+	query Query,
+	pageSize int,
+	token string,
+) (DocumentPage[T], error) {
+	if g == nil {
+		return DocumentPage[T]{}, errors.New("firevault: nil CollectionGroupRef")
+	}
+
+	return g.collection.Page(ctx, query, pageSize, token)
+}
+
+// check the query can be run against a collection group
+func checkGroupQuery(query Query) error {
+	if len(query.ids) > 0 {
+		return errors.New("firevault: ID queries are not supported for collection groups")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package firevault
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// create a reference to a document in the comments subcollection of a post
+func testCommentRef(postID string, id string) *firestore.DocumentRef {
+	path := "projects/p/databases/d/documents/posts/" + postID + "/comments"
+	parent := &firestore.CollectionRef{ID: "comments", Path: path}
+
+	return &firestore.DocumentRef{Parent: parent, ID: id, Path: path + "/" + id}
+}
+
+func TestGroupBulkError(t *testing.T) {
+	type Comment struct {
+		Text string `firevault:"text"`
+	}
+
+	group := &CollectionRef[Comment]{}
+
+	writes := make(bulkWrites)
+	writes.add(testCommentRef("a", "1"), nil, true)
+	writes.add(testCommentRef("b", "1"), nil, true)
+	writes.add(testCommentRef("c", "1"), nil, false)
+
+	if len(writes) != 3 {
+		t.Fatalf("Expected 3 writes, got %d", len(writes))
+	}
+
+	writes["projects/p/databases/d/documents/posts/a/comments/1"].err = status.Error(codes.FailedPrecondition, "changed")
+	writes["projects/p/databases/d/documents/posts/b/comments/1"].err = status.Error(codes.FailedPrecondition, "changed")
+	writes["projects/p/databases/d/documents/posts/c/comments/1"].err = status.Error(codes.NotFound, "missing")
+
+	err := group.bulkError(nil, writes)
+
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected ConflictError, got %v", err)
+	}
+
+	wantConflicts := []string{"posts/a/comments/1", "posts/b/comments/1"}
+	if strings.Join(conflict.DocIDs, ",") != strings.Join(wantConflicts, ",") {
+		t.Errorf("Got conflicts %v, want %v", conflict.DocIDs, wantConflicts)
+	}
+
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "docPath: posts/c/comments/1") {
+		t.Errorf("Expected not found error with the document's path, got %v", err)
+	}
+
+	t.Run("Collection", func(t *testing.T) {
+		collection := &CollectionRef[Comment]{ref: &firestore.CollectionRef{}}
+
+		writes := make(bulkWrites)
+		writes.add(testCommentRef("a", "1"), nil, true)
+		writes["projects/p/databases/d/documents/posts/a/comments/1"].err = status.Error(codes.FailedPrecondition, "changed")
+
+		err := collection.bulkError(nil, writes)
+
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) || len(conflict.DocIDs) != 1 || conflict.DocIDs[0] != "1" {
+			t.Errorf("Expected ConflictError with the document's ID, got %v", err)
+		}
+	})
+}
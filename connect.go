@@ -3,6 +3,7 @@ package firevault
 import (
 	"context"
 	"errors"
+	"reflect"
 
 	"cloud.google.com/go/firestore"
 )
@@ -60,3 +61,24 @@ func (c *Connection) RegisterTransformation(name string, transformation Transfor
 
 	return c.validator.registerTransformation(name, transformation)
 }
+
+// Register a new named regex, which can be used with the
+// "regex" rule (e.g. regex=postcode).
+func (c *Connection) RegisterRegex(name string, pattern string) error {
+	if c == nil {
+		return errors.New("firevault: nil Connection")
+	}
+
+	return c.validator.registerRegex(name, pattern)
+}
+
+// Register a new struct-level validation for the struct's type
+// (e.g. passing in Address{}), which is run after the fields'
+// rules have passed, for types which can't implement Validatable.
+func (c *Connection) RegisterStructValidation(structType interface{}, validation StructValidationFn) error {
+	if c == nil {
+		return errors.New("firevault: nil Connection")
+	}
+
+	return c.validator.registerStructValidation(reflect.TypeOf(structType), validation)
+}
@@ -0,0 +1,285 @@
+package firevault
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// the context keys of the structs being validated
+type structKey int
+
+const (
+	rootStructKey structKey = iota
+	parentStructKey
+)
+
+// RootStruct returns the top-level struct being validated, when
+// called from a validation or transformation function (with
+// the passed in context).
+//
+// It can be used by custom validations which depend on the
+// values of other fields.
+func RootStruct(ctx context.Context) (reflect.Value, bool) {
+	value, ok := ctx.Value(rootStructKey).(reflect.Value)
+	return value, ok
+}
+
+// ParentStruct returns the struct which holds the field being
+// validated, when called from a validation or transformation
+// function (with the passed in context). For top-level fields,
+// it's the same as the RootStruct.
+//
+// It can be used by custom validations which depend on the
+// values of sibling fields.
+func ParentStruct(ctx context.Context) (reflect.Value, bool) {
+	value, ok := ctx.Value(parentStructKey).(reflect.Value)
+	return value, ok
+}
+
+// get the cross-field validations, which look up other fields
+func (v *validator) crossFieldValidators() map[string]ValidationFn {
+	return map[string]ValidationFn{
+		"eqfield":          v.validateEqField,
+		"nefield":          v.validateNeField,
+		"gtfield":          v.validateGtField,
+		"ltfield":          v.validateLtField,
+		"required_with":    v.validateRequiredWith,
+		"required_without": v.validateRequiredWithout,
+		"required_if":      v.validateRequiredIf,
+	}
+}
+
+// checks if rule is a cross-field required rule, which
+// is applied even when the field is empty
+func isCrossFieldRequiredRule(rule string) bool {
+	name, _, _ := strings.Cut(rule, "=")
+	return name == "required_with" || name == "required_without" || name == "required_if"
+}
+
+// get the value of the field at the dot-separated path, relative
+// to the struct holding the validated field, or to the top-level
+// struct if not found there; the value is invalid if the path
+// goes through a nil pointer or a missing map key
+func (v *validator) crossFieldValue(ctx context.Context, fieldPath string, path string) (reflect.Value, error) {
+	if path == "" {
+		return reflect.Value{}, errors.New("firevault: provide a field param - " + fieldPath)
+	}
+
+	for _, key := range []structKey{parentStructKey, rootStructKey} {
+		structValue, ok := ctx.Value(key).(reflect.Value)
+		if !ok {
+			continue
+		}
+
+		if _, err := v.getFieldType(structValue.Type(), path); err != nil {
+			continue
+		}
+
+		value, err := v.getFieldValue(structValue, path)
+		if err != nil {
+			return reflect.Value{}, nil
+		}
+
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}, nil
+			}
+
+			value = value.Elem()
+		}
+
+		return value, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("firevault: unknown field %s - %s", path, fieldPath)
+}
+
+// compare two numbers, strings or times, reporting
+// whether they could be compared
+func compareValues(a reflect.Value, b reflect.Value) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+
+	timeType := reflect.TypeOf(time.Time{})
+
+	switch {
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int()), true
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint()), true
+	case isNumeric(a.Type()) && isNumeric(b.Type()):
+		return cmp.Compare(asFloat64(a), asFloat64(b)), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return cmp.Compare(a.String(), b.String()), true
+	case a.Type().ConvertibleTo(timeType) && b.Type().ConvertibleTo(timeType):
+		at := a.Convert(timeType).Interface().(time.Time)
+		bt := b.Convert(timeType).Interface().(time.Time)
+
+		return at.Compare(bt), true
+	}
+
+	return 0, false
+}
+
+// get the number's value as a float64
+func asFloat64(value reflect.Value) float64 {
+	switch {
+	case value.CanInt():
+		return float64(value.Int())
+	case value.CanUint():
+		return float64(value.Uint())
+	default:
+		return value.Float()
+	}
+}
+
+// checks if both values are equal
+func valuesEqual(a reflect.Value, b reflect.Value) bool {
+	if c, ok := compareValues(a, b); ok {
+		return c == 0
+	}
+
+	if !a.IsValid() || !b.IsValid() || !a.CanInterface() || !b.CanInterface() {
+		return false
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// validates if field's value is equal to the other field's value
+func (v *validator) validateEqField(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	other, err := v.crossFieldValue(ctx, fieldPath, param)
+	if err != nil {
+		return false, err
+	}
+
+	return valuesEqual(fieldValue, other), nil
+}
+
+// validates if field's value is not equal to the other field's value
+func (v *validator) validateNeField(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	other, err := v.crossFieldValue(ctx, fieldPath, param)
+	if err != nil {
+		return false, err
+	}
+
+	return !valuesEqual(fieldValue, other), nil
+}
+
+// validates if field's value is greater than the other field's value
+func (v *validator) validateGtField(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	c, err := v.compareField(ctx, fieldPath, fieldValue, param)
+	return c > 0, err
+}
+
+// validates if field's value is less than the other field's value
+func (v *validator) validateLtField(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	c, err := v.compareField(ctx, fieldPath, fieldValue, param)
+	return c < 0, err
+}
+
+// compare field's value to the other field's value; a missing
+// other value is treated as equal, so both gtfield and ltfield fail
+func (v *validator) compareField(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (int, error) {
+	other, err := v.crossFieldValue(ctx, fieldPath, param)
+	if err != nil {
+		return 0, err
+	}
+
+	if !other.IsValid() {
+		return 0, nil
+	}
+
+	c, ok := compareValues(fieldValue, other)
+	if !ok {
+		return 0, fmt.Errorf("firevault: can't compare field with %s - %s", param, fieldPath)
+	}
+
+	return c, nil
+}
+
+// validates if field is not zero, when the other field isn't
+func (v *validator) validateRequiredWith(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	other, err := v.crossFieldValue(ctx, fieldPath, param)
+	if err != nil {
+		return false, err
+	}
+
+	return !hasValue(other) || hasValue(fieldValue), nil
+}
+
+// validates if field is not zero, when the other field is
+func (v *validator) validateRequiredWithout(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	other, err := v.crossFieldValue(ctx, fieldPath, param)
+	if err != nil {
+		return false, err
+	}
+
+	return hasValue(other) || hasValue(fieldValue), nil
+}
+
+// validates if field is not zero, when the other field
+// has the value (e.g. required_if=status active)
+func (v *validator) validateRequiredIf(
+	ctx context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	path, value, found := strings.Cut(param, " ")
+	if !found {
+		return false, errors.New("firevault: required_if param must be in the format 'field value' - " + fieldPath)
+	}
+
+	other, err := v.crossFieldValue(ctx, fieldPath, path)
+	if err != nil {
+		return false, err
+	}
+
+	if !other.IsValid() || fmt.Sprint(other.Interface()) != strings.TrimSpace(value) {
+		return true, nil
+	}
+
+	return hasValue(fieldValue), nil
+}
@@ -0,0 +1,176 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCrossFieldRules(t *testing.T) {
+	v := newValidator()
+
+	type Period struct {
+		Start time.Time `firevault:"start,gtfield=created"`
+		End   time.Time `firevault:"end,gtfield=start"`
+	}
+
+	type TestStruct struct {
+		Password        string    `firevault:"password"`
+		PasswordConfirm string    `firevault:"password_confirm,eqfield=password"`
+		Username        string    `firevault:"username,nefield=password"`
+		Min             int       `firevault:"min"`
+		Max             float64   `firevault:"max,omitempty,gtfield=min"`
+		Period          *Period   `firevault:"period,omitempty"`
+		Phone           string    `firevault:"phone,required_without=email"`
+		Email           string    `firevault:"email,required_with=phone_ext"`
+		PhoneExt        string    `firevault:"phone_ext"`
+		Status          string    `firevault:"status"`
+		Reason          string    `firevault:"reason,required_if=status closed"`
+		Created         time.Time `firevault:"created"`
+		Deadline        time.Time `firevault:"deadline,omitempty,ltfield=period.end"`
+	}
+
+	now := time.Now()
+
+	valid := func() *TestStruct {
+		return &TestStruct{
+			Password:        "secret",
+			PasswordConfirm: "secret",
+			Username:        "bob",
+			Min:             1,
+			Max:             1.5,
+			Period:          &Period{now, now.Add(time.Hour)},
+			Phone:           "123",
+			Status:          "open",
+			Created:         now.Add(-time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*TestStruct)
+		wantErr  bool
+		wantPath string
+	}{
+		{"Valid", func(*TestStruct) {}, false, ""},
+		{"Not equal", func(d *TestStruct) { d.PasswordConfirm = "other" }, true, "password_confirm"},
+		{"Equal", func(d *TestStruct) { d.Username = "secret" }, true, "username"},
+		{"Not greater number", func(d *TestStruct) { d.Max = 1 }, true, "max"},
+		{"Not greater time", func(d *TestStruct) { d.Period.End = now }, true, "period.end"},
+		{"Required without", func(d *TestStruct) { d.Phone = "" }, true, "phone"},
+		{"Not required without", func(d *TestStruct) { d.Phone, d.Email = "", "a@b.com" }, false, ""},
+		{"Required with", func(d *TestStruct) { d.PhoneExt = "1" }, true, "email"},
+		{"Required if", func(d *TestStruct) { d.Status = "closed" }, true, "reason"},
+		{"Not required if", func(d *TestStruct) { d.Status, d.Reason = "closed", "done" }, false, ""},
+		{"Nested path", func(d *TestStruct) { d.Deadline = now }, false, ""},
+		{"Not less nested path", func(d *TestStruct) { d.Deadline = now.Add(time.Hour) }, true, "deadline"},
+		{"Nil path", func(d *TestStruct) { d.Period, d.Deadline = nil, now }, true, "deadline"},
+		{"Top-level path", func(d *TestStruct) { d.Created = now }, true, "period.start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := valid()
+			tt.modify(data)
+
+			_, err := v.validate(context.Background(), data, validationOpts{method: create})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validator.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			var fe FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("Expected FieldError, got %v", err)
+			}
+			if fe.Path() != tt.wantPath {
+				t.Errorf("Got error on %s, want %s", fe.Path(), tt.wantPath)
+			}
+		})
+	}
+
+	t.Run("Unknown field", func(t *testing.T) {
+		type InvalidStruct struct {
+			Name string `firevault:"name,eqfield=missing"`
+		}
+
+		_, err := v.validate(context.Background(), &InvalidStruct{"a"}, validationOpts{method: create})
+
+		var fe FieldError
+		if err == nil || errors.As(err, &fe) {
+			t.Errorf("Expected configuration error, got %v", err)
+		}
+	})
+}
+
+func TestMethodRequiredRules(t *testing.T) {
+	v := newValidator()
+
+	type TestStruct struct {
+		Name string `firevault:"name,required_create"`
+	}
+
+	_, err := v.validate(context.Background(), &TestStruct{}, validationOpts{method: create})
+	if err == nil {
+		t.Error("Expected required_create to fail during create")
+	}
+
+	_, err = v.validate(context.Background(), &TestStruct{}, validationOpts{method: update})
+	if err != nil {
+		t.Errorf("Expected required_create to be ignored during update, got %v", err)
+	}
+}
+
+func TestParentStruct(t *testing.T) {
+	v := newValidator()
+
+	type Item struct {
+		Kind string `firevault:"kind"`
+		Code string `firevault:"code,code"`
+	}
+
+	type TestStruct struct {
+		Kind string `firevault:"kind"`
+		Item Item   `firevault:"item"`
+	}
+
+	err := v.registerValidation(
+		"code",
+		func(ctx context.Context, _ string, value reflect.Value, _ string) (bool, error) {
+			parent, ok := ParentStruct(ctx)
+			if !ok {
+				return false, errors.New("no parent")
+			}
+
+			root, ok := RootStruct(ctx)
+			if !ok {
+				return false, errors.New("no root")
+			}
+
+			prefix := parent.FieldByName("Kind").String() + root.FieldByName("Kind").String()
+			return value.String() == prefix+"-1", nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data := &TestStruct{Kind: "a", Item: Item{Kind: "b", Code: "ba-1"}}
+
+	_, err = v.validate(context.Background(), data, validationOpts{method: create})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	data.Item.Code = "ab-1"
+
+	_, err = v.validate(context.Background(), data, validationOpts{method: create})
+	if err == nil {
+		t.Error("Expected validation to fail")
+	}
+}
@@ -0,0 +1,295 @@
+package firevault
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// the data of a document snapshot (implemented by
+// *firestore.DocumentSnapshot)
+type snapshotData interface {
+	Data() map[string]interface{}
+	DataTo(p interface{}) error
+}
+
+// decode the snapshot's data into the value pointed to by dst, using
+// firevault tag names for structs, and firestore's own decoding for
+// other types (e.g. maps)
+func decodeSnapshot(docSnap snapshotData, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+
+	if value.Kind() == reflect.Pointer && !value.IsNil() && value.Elem().Kind() != reflect.Struct {
+		return docSnap.DataTo(dst)
+	}
+
+	return decode(docSnap.Data(), dst)
+}
+
+// decode Firestore document data into the struct pointed to by dst,
+// matching stored field names against the first rule of each
+// field's firevault tag (the same name used when writing data)
+func decode(data map[string]interface{}, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return errors.New("firevault: decode destination must be a non-nil pointer")
+	}
+
+	value = value.Elem()
+
+	if value.Kind() != reflect.Struct {
+		return errors.New("firevault: decode destination must be a pointer to a struct")
+	}
+
+	return decodeStruct(data, value, "")
+}
+
+// set struct's fields from a data map, using firevault tag names
+func decodeStruct(data map[string]interface{}, structValue reflect.Value, path string) error {
+	structType := structValue.Type()
+
+	for i := 0; i < structValue.NumField(); i++ {
+		fieldType := structType.Field(i)
+
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		fieldName, ok := decodeFieldName(fieldType)
+		if !ok {
+			continue
+		}
+
+		src, ok := data[fieldName]
+		if !ok {
+			continue
+		}
+
+		fieldPath := fieldName
+		if path != "" {
+			fieldPath = path + "." + fieldName
+		}
+
+		err := decodeValue(src, structValue.Field(i), fieldPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// get the name under which a struct field is stored in Firestore,
+// or false if the field is ignored
+func decodeFieldName(fieldType reflect.StructField) (string, bool) {
+	tag := fieldType.Tag.Get("firevault")
+
+	if tag == "" || tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return fieldType.Name, true
+	}
+
+	return name, true
+}
+
+// set a single value, converting it to the destination's type
+func decodeValue(src interface{}, dst reflect.Value, path string) error {
+	// a null value resets nillable types and is ignored otherwise
+	if src == nil {
+		switch dst.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+
+		return nil
+	}
+
+	srcValue := reflect.ValueOf(src)
+
+	// values which Firestore already returns in their final form
+	// (e.g. time.Time, []byte, *latlng.LatLng or *firestore.DocumentRef)
+	if srcValue.Type().AssignableTo(dst.Type()) {
+		dst.Set(srcValue)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+
+		return decodeValue(src, dst.Elem(), path)
+	case reflect.Interface:
+		if dst.NumMethod() == 0 {
+			dst.Set(srcValue)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := src.(bool); ok {
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.String:
+		if s, ok := src.(string); ok {
+			dst.SetString(s)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeInt(src, dst, path)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decodeUint(src, dst, path)
+	case reflect.Float32, reflect.Float64:
+		return decodeFloat(src, dst, path)
+	case reflect.Struct:
+		if m, ok := src.(map[string]interface{}); ok && dst.Type() != reflect.TypeOf(time.Time{}) {
+			return decodeStruct(m, dst, path)
+		}
+	case reflect.Map:
+		if m, ok := src.(map[string]interface{}); ok {
+			return decodeMap(m, dst, path)
+		}
+	case reflect.Slice, reflect.Array:
+		if s, ok := src.([]interface{}); ok {
+			return decodeSlice(s, dst, path)
+		}
+	}
+
+	return decodeTypeError(src, dst, path)
+}
+
+// set an integer value, rejecting overflows and fractional numbers
+func decodeInt(src interface{}, dst reflect.Value, path string) error {
+	var i int64
+
+	switch x := src.(type) {
+	case int64:
+		i = x
+	case float64:
+		i = int64(x)
+		if float64(i) != x {
+			return fmt.Errorf("firevault: float %f does not fit into %s - %s", x, dst.Type(), path)
+		}
+	default:
+		return decodeTypeError(src, dst, path)
+	}
+
+	if dst.OverflowInt(i) {
+		return fmt.Errorf("firevault: value %d overflows %s - %s", i, dst.Type(), path)
+	}
+
+	dst.SetInt(i)
+	return nil
+}
+
+// set an unsigned integer value, rejecting overflows and fractional numbers
+func decodeUint(src interface{}, dst reflect.Value, path string) error {
+	var u uint64
+
+	switch x := src.(type) {
+	case int64:
+		if x < 0 {
+			return fmt.Errorf("firevault: value %d overflows %s - %s", x, dst.Type(), path)
+		}
+
+		u = uint64(x)
+	case float64:
+		u = uint64(x)
+		if x < 0 || float64(u) != x {
+			return fmt.Errorf("firevault: float %f does not fit into %s - %s", x, dst.Type(), path)
+		}
+	default:
+		return decodeTypeError(src, dst, path)
+	}
+
+	if dst.OverflowUint(u) {
+		return fmt.Errorf("firevault: value %d overflows %s - %s", u, dst.Type(), path)
+	}
+
+	dst.SetUint(u)
+	return nil
+}
+
+// set a floating point value
+func decodeFloat(src interface{}, dst reflect.Value, path string) error {
+	var f float64
+
+	switch x := src.(type) {
+	case float64:
+		f = x
+	case int64:
+		f = float64(x)
+	default:
+		return decodeTypeError(src, dst, path)
+	}
+
+	if dst.OverflowFloat(f) {
+		return fmt.Errorf("firevault: value %f overflows %s - %s", f, dst.Type(), path)
+	}
+
+	dst.SetFloat(f)
+	return nil
+}
+
+// set a map value, converting keys to the map's key type
+func decodeMap(src map[string]interface{}, dst reflect.Value, path string) error {
+	mapType := dst.Type()
+
+	if mapType.Key().Kind() != reflect.String {
+		return fmt.Errorf("firevault: map key type must be a string - %s", path)
+	}
+
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(mapType, len(src)))
+	}
+
+	for key, val := range src {
+		elem := reflect.New(mapType.Elem()).Elem()
+
+		err := decodeValue(val, elem, path+"."+key)
+		if err != nil {
+			return err
+		}
+
+		dst.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), elem)
+	}
+
+	return nil
+}
+
+// set a slice or array value
+func decodeSlice(src []interface{}, dst reflect.Value, path string) error {
+	if dst.Kind() == reflect.Array {
+		if len(src) > dst.Len() {
+			return fmt.Errorf("firevault: array of length %d cannot hold %d values - %s", dst.Len(), len(src), path)
+		}
+
+		// zero any trailing elements not present in the source
+		dst.Set(reflect.Zero(dst.Type()))
+	} else {
+		dst.Set(reflect.MakeSlice(dst.Type(), len(src), len(src)))
+	}
+
+	for i, val := range src {
+		err := decodeValue(val, dst.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// build a type mismatch error
+func decodeTypeError(src interface{}, dst reflect.Value, path string) error {
+	return fmt.Errorf("firevault: cannot decode %T into %s - %s", src, dst.Type(), path)
+}
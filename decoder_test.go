@@ -0,0 +1,160 @@
+package firevault
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	type Address struct {
+		Line1 string `firevault:"line_1,required"`
+		City  string `firevault:",omitempty"`
+		Notes string `firevault:"-"`
+	}
+
+	type TestStruct struct {
+		Email     string            `firevault:"email_address,required,email"`
+		Age       int               `firevault:"age,min=18"`
+		Score     float64           `firevault:"score"`
+		Active    bool              `firevault:"active"`
+		CreatedAt time.Time         `firevault:"created_at,omitempty"`
+		Address   *Address          `firevault:"address,omitempty"`
+		History   []Address         `firevault:"history"`
+		Labels    map[string]string `firevault:"labels"`
+		Counts    [2]uint8          `firevault:"counts"`
+		Untagged  string
+	}
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		want    TestStruct
+		wantErr bool
+	}{
+		{
+			name: "Valid data",
+			data: map[string]interface{}{
+				"email_address": "john@example.com",
+				"age":           int64(30),
+				"score":         int64(7),
+				"active":        true,
+				"created_at":    now,
+				"address": map[string]interface{}{
+					"line_1": "123 Main St",
+					"City":   "Anytown",
+					"Notes":  "ignored",
+				},
+				"history": []interface{}{
+					map[string]interface{}{"line_1": "1 High Street"},
+				},
+				"labels":   map[string]interface{}{"role": "admin"},
+				"counts":   []interface{}{int64(1)},
+				"Untagged": "ignored",
+			},
+			want: TestStruct{
+				Email:     "john@example.com",
+				Age:       30,
+				Score:     7,
+				Active:    true,
+				CreatedAt: now,
+				Address:   &Address{Line1: "123 Main St", City: "Anytown"},
+				History:   []Address{{Line1: "1 High Street"}},
+				Labels:    map[string]string{"role": "admin"},
+				Counts:    [2]uint8{1, 0},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Null values",
+			data: map[string]interface{}{
+				"address": nil,
+				"history": nil,
+			},
+			want:    TestStruct{},
+			wantErr: false,
+		},
+		{
+			name:    "Mismatched type",
+			data:    map[string]interface{}{"age": "thirty"},
+			wantErr: true,
+		},
+		{
+			name:    "Fractional integer",
+			data:    map[string]interface{}{"age": 30.5},
+			wantErr: true,
+		},
+		{
+			name:    "Overflowing unsigned integer",
+			data:    map[string]interface{}{"counts": []interface{}{int64(256)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TestStruct
+
+			err := decode(tt.data, &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// a snapshot which decodes its data like firestore does for maps
+type testSnapshotData map[string]interface{}
+
+func (s testSnapshotData) Data() map[string]interface{} {
+	return s
+}
+
+func (s testSnapshotData) DataTo(p interface{}) error {
+	dst, ok := p.(*map[string]interface{})
+	if !ok {
+		return errors.New("unsupported destination")
+	}
+
+	*dst = s
+	return nil
+}
+
+func TestDecodeSnapshot(t *testing.T) {
+	data := testSnapshotData{"email_address": "john@example.com"}
+
+	t.Run("Map", func(t *testing.T) {
+		var got map[string]interface{}
+
+		err := decodeSnapshot(data, &got)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, map[string]interface{}(data)) {
+			t.Errorf("decodeSnapshot() = %v, want %v", got, data)
+		}
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		var got struct {
+			Email string `firevault:"email_address"`
+		}
+
+		err := decodeSnapshot(data, &got)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got.Email != "john@example.com" {
+			t.Errorf("Expected email to be decoded using its tag name, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package firevault
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrNotFound is returned when a requested document does not
+	// exist (e.g. when calling FindOne, or Update with an ID of
+	// a missing document).
+	ErrNotFound = errors.New("firevault: document not found")
+	// ErrAlreadyExists is returned when trying to create a document
+	// with an ID which is already in use.
+	ErrAlreadyExists = errors.New("firevault: document already exists")
+	// ErrFailedPrecondition is returned when a Firestore precondition
+	// was not met (e.g. a missing index for a query).
+	ErrFailedPrecondition = errors.New("firevault: failed precondition")
+	// ErrBatchTooLarge is returned when committing a Batch which
+	// holds more writes than Firestore allows in a single commit.
+	ErrBatchTooLarge = errors.New("firevault: batch exceeds 500 writes")
+	// ErrInvalidPageToken is returned when a page token can't be
+	// decoded, or was created for a Query with different orders.
+	ErrInvalidPageToken = errors.New("firevault: invalid page token")
+)
+
+// A ConflictError is returned when documents have changed since
+// the version specified with the IfUnchangedSince or IfMatch options.
+//
+// It matches ErrFailedPrecondition, when checked with errors.Is.
+type ConflictError struct {
+	// DocIDs holds the IDs of the documents which have changed
+	// (or their paths, for collection group writes).
+	DocIDs []string
+}
+
+// Error returns the ConflictError's error message
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("firevault: documents have changed (docIDs: %s)", strings.Join(e.DocIDs, ", "))
+}
+
+// Unwrap returns ErrFailedPrecondition
+func (e *ConflictError) Unwrap() error {
+	return ErrFailedPrecondition
+}
+
+// wrap a Firestore error with the matching firevault error, keeping
+// the original error in the chain, so both can be checked with errors.Is
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// skip errors which have already been wrapped
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrAlreadyExists) ||
+		errors.Is(err, ErrFailedPrecondition) {
+		return err
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case codes.AlreadyExists:
+		return fmt.Errorf("%w: %w", ErrAlreadyExists, err)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %w", ErrFailedPrecondition, err)
+	default:
+		return err
+	}
+}
+
+// add the document's ID to an error
+func docError(err error, docID string) error {
+	return fmt.Errorf("%w (docID: %s)", err, docID)
+}
+
+// add the document's path to an error
+func docPathError(err error, docPath string) error {
+	return fmt.Errorf("%w (docPath: %s)", err, docPath)
+}
@@ -0,0 +1,84 @@
+package firevault
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Not found", status.Error(codes.NotFound, "missing"), ErrNotFound},
+		{"Already exists", status.Error(codes.AlreadyExists, "exists"), ErrAlreadyExists},
+		{"Failed precondition", status.Error(codes.FailedPrecondition, "changed"), ErrFailedPrecondition},
+		{"Other code", status.Error(codes.Internal, "internal"), nil},
+		{"Not a status", errors.New("other"), nil},
+	}
+
+	sentinels := []error{ErrNotFound, ErrAlreadyExists, ErrFailedPrecondition}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapError(tt.err)
+
+			for _, sentinel := range sentinels {
+				want := sentinel == tt.want
+				if errors.Is(got, sentinel) != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", got, sentinel, !want, want)
+				}
+			}
+
+			// the original error is kept in the chain
+			if !errors.Is(got, tt.err) {
+				t.Errorf("Expected %v to wrap %v", got, tt.err)
+			}
+
+			if status.Code(tt.err) != codes.Unknown && status.Code(got) != status.Code(tt.err) {
+				t.Errorf("Expected status code %v, got %v", status.Code(tt.err), status.Code(got))
+			}
+
+			// wrapping again keeps the error as it is
+			if again := wrapError(got); again != got {
+				t.Errorf("Expected wrapped error to be kept, got %v", again)
+			}
+		})
+	}
+
+	t.Run("Nil", func(t *testing.T) {
+		if err := wrapError(nil); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	})
+
+	t.Run("Document error", func(t *testing.T) {
+		err := docError(wrapError(status.Error(codes.NotFound, "missing")), "abc")
+
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected %v to match ErrNotFound", err)
+		}
+
+		var statusErr interface{ GRPCStatus() *status.Status }
+		if !errors.As(err, &statusErr) || statusErr.GRPCStatus().Code() != codes.NotFound {
+			t.Errorf("Expected %v to hold the gRPC status", err)
+		}
+	})
+}
+
+func TestConflictError(t *testing.T) {
+	var err error = docError(&ConflictError{[]string{"a", "b"}}, "a")
+
+	if !errors.Is(err, ErrFailedPrecondition) {
+		t.Errorf("Expected %v to match ErrFailedPrecondition", err)
+	}
+
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.DocIDs) != 2 {
+		t.Errorf("Expected %v to hold a ConflictError", err)
+	}
+}
@@ -3,6 +3,7 @@ package firevault
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // fieldError contains a single field's validation error along
@@ -13,10 +14,13 @@ type fieldError struct {
 	tag         string
 	field       string
 	structField string
+	path        string
+	structPath  string
 	value       interface{}
 	param       string
 	kind        reflect.Kind
 	typ         reflect.Type
+	err         error
 }
 
 // FieldError contains all functions to get error details
@@ -31,6 +35,13 @@ type FieldError interface {
 	Field() string
 	// StructField returns the field's actual name from the struct
 	StructField() string
+	// Path returns the field's full path, with the tag names taking
+	// precedence over the fields' actual names, and map keys and
+	// slice indices included (e.g. address.lines[2])
+	Path() string
+	// StructPath returns the field's full path, using the fields'
+	// actual names from the structs (e.g. Address.Lines[2])
+	StructPath() string
 	// Value returns the actual field's value in case needed for
 	// creating the error message
 	Value() interface{}
@@ -68,6 +79,18 @@ func (fe *fieldError) StructField() string {
 	return fe.structField
 }
 
+// Path returns the field's full path, with the tag names taking
+// precedence over the fields' actual names
+func (fe *fieldError) Path() string {
+	return fe.path
+}
+
+// StructPath returns the field's full path, using the fields'
+// actual names from the structs
+func (fe *fieldError) StructPath() string {
+	return fe.structPath
+}
+
 // Value returns the actual field's value in case needed for creating
 // the error message
 func (fe *fieldError) Value() interface{} {
@@ -92,5 +115,42 @@ func (fe *fieldError) Type() reflect.Type {
 
 // Error returns the fieldError's error message
 func (fe *fieldError) Error() string {
+	if fe.err != nil {
+		return fmt.Sprintf("firevault: struct validation for '%s' failed - %s", fe.field, fe.err)
+	}
+
 	return fmt.Sprintf("firevault: field validation for '%s' failed on the '%s' tag", fe.field, fe.tag)
 }
+
+// Unwrap returns the error returned by a struct-level
+// validation, if any
+func (fe *fieldError) Unwrap() error {
+	return fe.err
+}
+
+// ValidationErrors holds the errors of all fields which failed
+// validation, when the CollectAllErrors option is used
+type ValidationErrors []FieldError
+
+// Error returns the error messages of all fields, one per line
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(ve))
+
+	for _, fe := range ve {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the field errors, so they can be
+// checked using errors.Is and errors.As
+func (ve ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(ve))
+
+	for _, fe := range ve {
+		errs = append(errs, fe)
+	}
+
+	return errs
+}
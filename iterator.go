@@ -0,0 +1,179 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+// Done is returned by DocumentIterator's Next method
+// when the iteration is complete.
+var Done = iterator.Done
+
+// the max number of documents fetched at once by ID
+const fetchBatchSize = 100
+
+// A Firevault DocumentIterator is an iterator over Firestore
+// documents which match a Query. Documents are fetched and
+// decoded lazily, as Next is called.
+type DocumentIterator[T interface{}] struct {
+	collection *CollectionRef[T]
+	snapshots  *snapshotIterator
+}
+
+// an iterator over raw document snapshots, either fetched by ID
+// (in batches) or by running a query
+type snapshotIterator struct {
+	docRefs []*firestore.DocumentRef
+	getAll  func([]*firestore.DocumentRef) ([]*firestore.DocumentSnapshot, error)
+	buffer  []*firestore.DocumentSnapshot
+	query   *firestore.DocumentIterator
+	err     error
+}
+
+// Iter returns a DocumentIterator over all Firestore
+// documents which match provided Query.
+//
+// The iterator must be stopped (using Stop), unless
+// Next has returned Done or an error.
+func (c *CollectionRef[T]) Iter(ctx context.Context, query Query) *DocumentIterator[T] {
+	if c == nil {
+		return &DocumentIterator[T]{
+			snapshots: &snapshotIterator{err: errors.New("firevault: nil CollectionRef")},
+		}
+	}
+
+	return &DocumentIterator[T]{c, c.snapshots(ctx, query, nil)}
+}
+
+// Next returns the next document. Its second return value
+// is Done if there are no more documents.
+//
+// If the Query specifies IDs of documents which do not exist,
+// an error wrapping ErrNotFound is returned for each of them.
+func (it *DocumentIterator[T]) Next() (Document[T], error) {
+	docSnap, err := it.snapshots.next()
+	if err != nil {
+		return Document[T]{}, err
+	}
+
+	return it.collection.decodeDoc(docSnap)
+}
+
+// Stop stops the iterator, freeing its resources.
+// Subsequent calls to Next will return Done.
+//
+// It is not necessary to call Stop after Next
+// has returned Done or an error.
+func (it *DocumentIterator[T]) Stop() {
+	it.snapshots.stop()
+}
+
+// create an iterator over the snapshots of documents which match the
+// query, using the transaction if one is provided
+func (c *CollectionRef[T]) snapshots(
+	ctx context.Context,
+	query Query,
+	tx *firestore.Transaction,
+) *snapshotIterator {
+	if len(query.ids) > 0 {
+		docRefs := make([]*firestore.DocumentRef, 0, len(query.ids))
+
+		for _, docID := range query.ids {
+			docRefs = append(docRefs, c.ref.Doc(docID))
+		}
+
+		getAll := func(refs []*firestore.DocumentRef) ([]*firestore.DocumentSnapshot, error) {
+			return c.connection.client.GetAll(ctx, refs)
+		}
+
+		if tx != nil {
+			getAll = tx.GetAll
+		}
+
+		return &snapshotIterator{docRefs: docRefs, getAll: getAll}
+	}
+
+	builtQuery, err := c.buildQuery(query)
+	if err != nil {
+		return &snapshotIterator{err: err}
+	}
+
+	if tx != nil {
+		return &snapshotIterator{query: tx.Documents(builtQuery)}
+	}
+
+	return &snapshotIterator{query: builtQuery.Documents(ctx)}
+}
+
+// get the next snapshot, or iterator.Done once exhausted
+func (it *snapshotIterator) next() (*firestore.DocumentSnapshot, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	docSnap, err := it.fetchNext()
+	if err != nil {
+		it.stop()
+		it.err = err
+		return nil, err
+	}
+
+	return docSnap, nil
+}
+
+// fetch the next snapshot from the query, or from the next batch of IDs
+func (it *snapshotIterator) fetchNext() (*firestore.DocumentSnapshot, error) {
+	if it.query != nil {
+		docSnap, err := it.query.Next()
+		if err == iterator.Done {
+			return nil, Done
+		}
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		return docSnap, nil
+	}
+
+	if len(it.buffer) == 0 {
+		if len(it.docRefs) == 0 {
+			return nil, Done
+		}
+
+		end := min(fetchBatchSize, len(it.docRefs))
+
+		snapshots, err := it.getAll(it.docRefs[:end])
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		it.docRefs = it.docRefs[end:]
+		it.buffer = snapshots
+	}
+
+	docSnap := it.buffer[0]
+	it.buffer = it.buffer[1:]
+
+	if !docSnap.Exists() {
+		return nil, docError(ErrNotFound, docSnap.Ref.ID)
+	}
+
+	return docSnap, nil
+}
+
+// stop the iterator, so that subsequent calls return iterator.Done
+func (it *snapshotIterator) stop() {
+	if it.query != nil {
+		it.query.Stop()
+	}
+
+	it.docRefs = nil
+	it.buffer = nil
+
+	if it.err == nil {
+		it.err = Done
+	}
+}
@@ -0,0 +1,56 @@
+//go:build go1.23
+
+package firevault
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over all Firestore documents which
+// match provided Query, for use with range-over-func loops.
+// Documents are fetched and decoded lazily.
+//
+// Breaking out of the loop stops the iteration. If an error
+// occurs, it is yielded once and the iteration ends.
+func (c *CollectionRef[T]) All(ctx context.Context, query Query) iter.Seq2[Document[T], error] {
+	return allDocuments(func() *DocumentIterator[T] {
+		return c.Iter(ctx, query)
+	})
+}
+
+// create a sequence over the documents of the iterator, which
+// is only created once the sequence is ranged over
+func allDocuments[T interface{}](newIter func() *DocumentIterator[T]) iter.Seq2[Document[T], error] {
+	return func(yield func(Document[T], error) bool) {
+		it := newIter()
+		defer it.Stop()
+
+		for {
+			doc, err := it.Next()
+			if err == Done {
+				return
+			}
+			if err != nil {
+				yield(Document[T]{}, err)
+				return
+			}
+
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over all Firestore documents in the
+// collection group which match provided Query, for use with
+// range-over-func loops. Documents are fetched and decoded lazily.
+//
+// Breaking out of the loop stops the iteration. If an error
+// occurs, it is yielded once and the iteration ends.
+func (g *CollectionGroupRef[T]) All(ctx context.Context, query Query) iter.Seq2[Document[T], error] {
+	return allDocuments(func() *DocumentIterator[T] {
+		return g.Iter(ctx, query)
+	})
+}
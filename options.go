@@ -1,5 +1,7 @@
 package firevault
 
+import "time"
+
 // used to determine how to parse options
 type methodType string
 
@@ -14,16 +16,21 @@ type validationOpts struct {
 	method             methodType
 	skipValidation     bool
 	emptyFieldsAllowed []string
+	collectAllErrors   bool
 }
 
 // A Firevault Options instance allows for the overriding of
-// default options for validation, creation and updating methods.
+// default options for validation, creation, updating and
+// deleting methods.
 //
 // Options values are immutable. Each Options method creates
 // a new instance - it does not modify the old.
 type Options struct {
 	// Skip all validations. Default is "false".
 	skipValidation bool
+	// Report every failing field, instead of only the first.
+	// Default is "false".
+	collectAllErrors bool
 	// Specify which fields (using "dot notation") should ignore
 	// the "omitempty" and "omitemptyupdate" tags.
 	//
@@ -49,12 +56,36 @@ type Options struct {
 	//
 	// Only used for creation method.
 	id string
+	// Specify the time at which documents must have been
+	// last updated, for them to be updated or deleted.
+	//
+	// Only used for updating and deleting methods.
+	unchangedSince time.Time
+	// Specify the versions documents must match, for them
+	// to be updated or deleted. Takes precedence over
+	// unchangedSince for the matching documents.
+	//
+	// Only used for updating and deleting methods.
+	versions []DocumentVersion
+	// Specify field transforms (e.g. increment) to be applied
+	// on top of the passed data.
+	//
+	// Only used for updating method.
+	transforms []updateTransform
+}
+
+// a field transform, applied to the field at path
+type updateTransform struct {
+	path      string
+	transform fieldTransform
+	value     interface{}
 }
 
 // Create a new Options instance.
 //
 // A Firevault Options instance allows for the overriding of
-// default options for validation, creation and updating methods.
+// default options for validation, creation, updating and
+// deleting methods.
 //
 // Options values are immutable. Each Options method creates
 // a new instance - it does not modify the old.
@@ -62,13 +93,24 @@ func NewOptions() Options {
 	return Options{}
 }
 
-// Skip all validations - the "name" tag, "omitempty" tags and
-// "ignore" tag will still be honoured.
+// Skip all validations - the "name" tag, "omitempty" tags,
+// "default" tags and "ignore" tag will still be honoured.
 func (o Options) SkipValidation() Options {
 	o.skipValidation = true
 	return o
 }
 
+// Report all fields which fail validation (including those of
+// nested structs, maps and slices) in a ValidationErrors error,
+// instead of stopping at the first one.
+//
+// Errors which aren't caused by a failed validation (e.g. an
+// unsupported field type) are still returned straight away.
+func (o Options) CollectAllErrors() Options {
+	o.collectAllErrors = true
+	return o
+}
+
 // Specify which field paths (using dot-separated strings)
 // should ignore the "omitempty" and "omitemptyupdate" tags.
 //
@@ -102,3 +144,65 @@ func (o Options) CustomID(id string) Options {
 	o.id = id
 	return o
 }
+
+// Specify the time at which documents must have been last
+// updated (e.g. the UpdateTime of a fetched Document), for
+// them to be updated or deleted. Documents which have changed
+// since are reported in a ConflictError.
+//
+// Firestore only supports exact matches, so documents last
+// updated at any other time (including earlier) will
+// also be reported.
+//
+// Only used for updating and deleting methods.
+func (o Options) IfUnchangedSince(t time.Time) Options {
+	o.unchangedSince = t
+	return o
+}
+
+// Specify document versions (i.e. fetched Documents), which
+// documents must match, for them to be updated or deleted.
+// Documents which have changed since are reported in a
+// ConflictError.
+//
+// Takes precedence over IfUnchangedSince for the matching
+// documents.
+//
+// Only used for updating and deleting methods.
+func (o Options) IfMatch(docs ...DocumentVersion) Options {
+	o.versions = append(o.versions, docs...)
+	return o
+}
+
+// Specify a field path (using dot-separated strings) whose
+// numeric value should be atomically incremented by n (which
+// can be negative, but not a uint or uint64). Any value for the
+// field in the data passed will be ignored.
+//
+// Only used for updating method.
+func (o Options) Increment(path string, n interface{}) Options {
+	o.transforms = append(o.transforms, updateTransform{path, increment, n})
+	return o
+}
+
+// Specify a field path (using dot-separated strings) to whose
+// array value the elements should be atomically added (unless
+// already present). Any value for the field in the data passed
+// will be ignored.
+//
+// Only used for updating method.
+func (o Options) ArrayUnion(path string, elems ...interface{}) Options {
+	o.transforms = append(o.transforms, updateTransform{path, arrayUnion, elems})
+	return o
+}
+
+// Specify a field path (using dot-separated strings) from whose
+// array value all instances of the elements should be atomically
+// removed. Any value for the field in the data passed will be
+// ignored.
+//
+// Only used for updating method.
+func (o Options) ArrayRemove(path string, elems ...interface{}) Options {
+	o.transforms = append(o.transforms, updateTransform{path, arrayRemove, elems})
+	return o
+}
@@ -0,0 +1,343 @@
+package firevault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// A Firevault DocumentPage holds a single page of Firestore
+// documents, along with the tokens used to fetch the pages
+// around it.
+type DocumentPage[T interface{}] struct {
+	// Docs holds the page's documents.
+	Docs []Document[T]
+	// NextToken is used to fetch the next page, or is empty
+	// if there are no more documents.
+	NextToken string
+	// PrevToken is used to fetch the previous page, or is
+	// empty if this is the first page.
+	PrevToken string
+}
+
+// the data encoded in a page token
+type pageToken struct {
+	// fetch the page before the cursor, instead of after it
+	Before bool `json:"b,omitempty"`
+	// the query's orders, to check the token is used with the same query
+	Orders []string `json:"o"`
+	// the cursor's values, one for each order
+	Values []tokenValue `json:"v"`
+}
+
+// a typed cursor value in a page token
+type tokenValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// Page fetches a single page (of up to pageSize documents) of the
+// Firestore documents which match provided Query.
+//
+// Pass an empty token to fetch the first page, and the returned
+// DocumentPage's NextToken or PrevToken to fetch the pages after
+// or before it. Tokens are opaque, URL-safe strings, which hold
+// the ordered field values of the page's last (or first) document,
+// so they can be handed to clients as they are.
+//
+// Documents are ordered by the Query's orders, followed by the
+// DocumentID (unless already ordered by it), so that documents
+// with the same values are never skipped or repeated. The Query
+// must not specify IDs, and its Limit, LimitToLast and cursor
+// methods are ignored. Its Offset is only applied to the first
+// page, as the tokens' cursors already skip the documents
+// before them. If the Query uses Select, the ordered fields
+// are added to it, as the tokens hold their values.
+//
+// If the token was created for a Query with different orders,
+// ErrInvalidPageToken is returned.
+func (c *CollectionRef[T]) Page(
+	ctx context.Context,
+	query Query,
+	pageSize int,
+	token string,
+) (DocumentPage[T], error) {
+	if c == nil {
+		return DocumentPage[T]{}, errors.New("firevault: nil CollectionRef")
+	}
+
+	if pageSize <= 0 {
+		return DocumentPage[T]{}, errors.New("firevault: page size must be greater than 0")
+	}
+
+	if len(query.ids) > 0 {
+		return DocumentPage[T]{}, errors.New("firevault: ID queries can't be paginated")
+	}
+
+	query = pageQuery(query, token)
+	keys := orderKeys(query.orders)
+
+	before := false
+
+	if token != "" {
+		t, err := decodePageToken(token, keys)
+		if err != nil {
+			return DocumentPage[T]{}, err
+		}
+
+		values, err := c.tokenCursor(t.Values)
+		if err != nil {
+			return DocumentPage[T]{}, err
+		}
+
+		before = t.Before
+
+		if before {
+			query.endBefore = values
+		} else {
+			query.startAfter = values
+		}
+	}
+
+	// fetch an extra document, to check if there are more
+	if before {
+		query.limitToLast = pageSize + 1
+	} else {
+		query.limit = pageSize + 1
+	}
+
+	docs, err := c.find(ctx, query, nil)
+	if err != nil {
+		return DocumentPage[T]{}, err
+	}
+
+	hasMore := len(docs) > pageSize
+	if hasMore && before {
+		docs = docs[1:]
+	} else if hasMore {
+		docs = docs[:pageSize]
+	}
+
+	page := DocumentPage[T]{Docs: docs}
+
+	if len(docs) == 0 {
+		return page, nil
+	}
+
+	// pages before a cursor always have a next page (the one they came from)
+	if hasMore || before {
+		page.NextToken, err = encodePageToken(false, query.orders, keys, docs[len(docs)-1])
+		if err != nil {
+			return DocumentPage[T]{}, err
+		}
+	}
+
+	// pages after a cursor always have a previous page (the one they came from)
+	if (hasMore && before) || (token != "" && !before) {
+		page.PrevToken, err = encodePageToken(true, query.orders, keys, docs[0])
+		if err != nil {
+			return DocumentPage[T]{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// prepare the query for fetching a page, clearing its limits and
+// cursors (which are set by the page), and its offset, unless
+// it's the first page (as the token's cursor already skips it)
+func pageQuery(query Query, token string) Query {
+	query.orders = pageOrders(query.orders)
+	query.startAt, query.startAfter, query.endBefore, query.endAt = nil, nil, nil, nil
+	query.limit, query.limitToLast = 0, 0
+
+	if token != "" {
+		query.offset = 0
+	}
+
+	// select the ordered fields, which the tokens are built from
+	if len(query.selects) > 0 {
+		selects := slices.Clone(query.selects)
+
+		for _, order := range query.orders {
+			if order.path != DocumentID && !slices.Contains(selects, order.path) {
+				selects = append(selects, order.path)
+			}
+		}
+
+		query.selects = selects
+	}
+
+	return query
+}
+
+// add the DocumentID as the last order (in the same direction as
+// the previous one), so every document has a unique position
+func pageOrders(orders []order) []order {
+	orderedByID := slices.ContainsFunc(orders, func(o order) bool {
+		return o.path == DocumentID
+	})
+	if orderedByID {
+		return orders
+	}
+
+	direction := Asc
+	if len(orders) > 0 {
+		direction = orders[len(orders)-1].direction
+	}
+
+	return append(slices.Clone(orders), order{DocumentID, direction})
+}
+
+// get a string representation of each order
+func orderKeys(orders []order) []string {
+	keys := make([]string, 0, len(orders))
+
+	for _, order := range orders {
+		keys = append(keys, order.path+":"+strconv.Itoa(int(order.direction)))
+	}
+
+	return keys
+}
+
+// encode the document's values for the orders as a page token
+func encodePageToken[T interface{}](
+	before bool,
+	orders []order,
+	orderKeys []string,
+	doc Document[T],
+) (string, error) {
+	if doc.snapshot == nil {
+		return "", errors.New("firevault: document has no snapshot")
+	}
+
+	values := make([]tokenValue, 0, len(orders))
+
+	for _, order := range orders {
+		var value interface{} = doc.snapshot.Ref
+
+		if order.path != DocumentID {
+			var err error
+
+			value, err = doc.snapshot.DataAtPath(strings.Split(order.path, "."))
+			if err != nil {
+				return "", errors.New("firevault: " + err.Error())
+			}
+		}
+
+		tv, err := newTokenValue(value)
+		if err != nil {
+			return "", fmt.Errorf("%w - %s", err, order.path)
+		}
+
+		values = append(values, tv)
+	}
+
+	data, err := json.Marshal(pageToken{before, orderKeys, values})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decode the page token, checking it was created for the same orders
+func decodePageToken(token string, orderKeys []string) (pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pageToken{}, ErrInvalidPageToken
+	}
+
+	var t pageToken
+
+	err = json.Unmarshal(data, &t)
+	if err != nil {
+		return pageToken{}, ErrInvalidPageToken
+	}
+
+	if !slices.Equal(t.Orders, orderKeys) || len(t.Values) != len(orderKeys) {
+		return pageToken{}, ErrInvalidPageToken
+	}
+
+	return t, nil
+}
+
+// convert a cursor value to a typed token value
+func newTokenValue(value interface{}) (tokenValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return tokenValue{"null", ""}, nil
+	case bool:
+		return tokenValue{"bool", strconv.FormatBool(v)}, nil
+	case int64:
+		return tokenValue{"int", strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return tokenValue{"float", strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case string:
+		return tokenValue{"string", v}, nil
+	case time.Time:
+		return tokenValue{"time", v.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return tokenValue{"bytes", base64.StdEncoding.EncodeToString(v)}, nil
+	case *firestore.DocumentRef:
+		return tokenValue{"ref", relativePath(v.Path)}, nil
+	default:
+		return tokenValue{}, fmt.Errorf("firevault: unsupported order value type %T", value)
+	}
+}
+
+// convert typed token values back to cursor values
+func (c *CollectionRef[T]) tokenCursor(values []tokenValue) ([]interface{}, error) {
+	cursor := make([]interface{}, 0, len(values))
+
+	for _, tv := range values {
+		if tv.Type == "ref" {
+			docRef := c.connection.client.Doc(tv.Value)
+			if docRef == nil {
+				return nil, ErrInvalidPageToken
+			}
+
+			cursor = append(cursor, docRef)
+			continue
+		}
+
+		value, err := tv.value()
+		if err != nil {
+			return nil, ErrInvalidPageToken
+		}
+
+		cursor = append(cursor, value)
+	}
+
+	return cursor, nil
+}
+
+// parse the token value (apart from document references)
+func (tv tokenValue) value() (interface{}, error) {
+	switch tv.Type {
+	case "null":
+		return nil, nil
+	case "bool":
+		return strconv.ParseBool(tv.Value)
+	case "int":
+		return strconv.ParseInt(tv.Value, 10, 64)
+	case "float":
+		return strconv.ParseFloat(tv.Value, 64)
+	case "string":
+		return tv.Value, nil
+	case "time":
+		return time.Parse(time.RFC3339Nano, tv.Value)
+	case "bytes":
+		return base64.StdEncoding.DecodeString(tv.Value)
+	default:
+		return nil, fmt.Errorf("firevault: unknown token value type %s", tv.Type)
+	}
+}
@@ -0,0 +1,130 @@
+package firevault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPageOrders(t *testing.T) {
+	tests := []struct {
+		name   string
+		orders []order
+		want   []order
+	}{
+		{"No orders", nil, []order{{DocumentID, Asc}}},
+		{"Field order", []order{{"age", Desc}}, []order{{"age", Desc}, {DocumentID, Desc}}},
+		{"Ordered by ID", []order{{DocumentID, Desc}, {"age", Asc}}, []order{{DocumentID, Desc}, {"age", Asc}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageOrders(tt.orders); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pageOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageQuery(t *testing.T) {
+	query := NewQuery().OrderBy("age", Asc).StartAt(18).Limit(5).Offset(10)
+
+	first := pageQuery(query, "")
+	if first.offset != 10 || first.limit != 0 || first.startAt != nil {
+		t.Errorf("Expected only the offset to be kept for the first page, got %+v", first)
+	}
+
+	next := pageQuery(query, "token")
+	if next.offset != 0 {
+		t.Errorf("Expected offset to be cleared for later pages, got %d", next.offset)
+	}
+
+	if !reflect.DeepEqual(next.orders, []order{{"age", Asc}, {DocumentID, Asc}}) {
+		t.Errorf("Expected page orders, got %v", next.orders)
+	}
+
+	selected := pageQuery(query.Select("name", "age").OrderBy("address.city", Desc), "")
+	if !reflect.DeepEqual(selected.selects, []string{"name", "age", "address.city"}) {
+		t.Errorf("Expected ordered fields to be selected, got %v", selected.selects)
+	}
+
+	if unselected := pageQuery(query, ""); unselected.selects != nil {
+		t.Errorf("Expected no fields to be selected, got %v", unselected.selects)
+	}
+}
+
+func TestTokenValues(t *testing.T) {
+	values := []interface{}{
+		nil,
+		true,
+		int64(-42),
+		3.14,
+		"Bobby Donev",
+		time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		[]byte("data"),
+	}
+
+	for _, value := range values {
+		tv, err := newTokenValue(value)
+		if err != nil {
+			t.Fatalf("newTokenValue(%v) unexpected error: %v", value, err)
+		}
+
+		got, err := tv.value()
+		if err != nil {
+			t.Fatalf("tokenValue.value() unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("tokenValue round trip = %v (%T), want %v (%T)", got, got, value, value)
+		}
+	}
+
+	_, err := newTokenValue([]interface{}{1})
+	if err == nil {
+		t.Error("Expected error for unsupported value type")
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	keys := orderKeys([]order{{"age", Asc}, {DocumentID, Asc}})
+
+	encode := func(token pageToken) string {
+		data, _ := json.Marshal(token)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	valid := pageToken{true, keys, []tokenValue{{"int", "18"}, {"ref", "users/abc"}}}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"Valid token", encode(valid), false},
+		{"Different orders", encode(pageToken{false, []string{"name:1"}, valid.Values}), true},
+		{"Missing values", encode(pageToken{false, keys, valid.Values[:1]}), true},
+		{"Not base64", "not a token!", true},
+		{"Not JSON", base64.RawURLEncoding.EncodeToString([]byte("{")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePageToken(tt.token, keys)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPageToken) {
+					t.Errorf("decodePageToken() error = %v, want ErrInvalidPageToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodePageToken() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, valid) {
+				t.Errorf("decodePageToken() = %v, want %v", got, valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package firevault
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// the parsed tag of a struct field, cached per struct type
+type fieldPlan struct {
+	// the field's index in the struct
+	index int
+	// the field's name, with the tag name taking precedence
+	name string
+	// the field's actual name from the struct
+	structName string
+	*rulePlan
+}
+
+// the parsed rules of a field (or of its elements, after a dive rule)
+type rulePlan struct {
+	// the rules before any dive rule (excluding the field name)
+	rules []string
+	// the validation and transformation rules, with their functions
+	parsed []parsedRule
+	// whether an omitempty tag is present
+	omitEmpty bool
+	// the methods of the method-scoped omitempty tags
+	omitEmptyMethods []methodType
+	// the rules applied to map keys (between keys and endkeys)
+	keys *rulePlan
+	// the rules applied to elements (after a dive rule)
+	elems *rulePlan
+	// the reason the rules are invalid, reported once they're applied
+	invalid string
+}
+
+// a validation or transformation rule, with its function resolved
+type parsedRule struct {
+	// the rule as written in the tag (e.g. max=10)
+	tag   string
+	name  string
+	param string
+	// whether it's a transformation (i.e. it has the transform= prefix)
+	transform      bool
+	validation     ValidationFn
+	transformation transformationFn
+	// whether it's applied to empty fields
+	required bool
+	// the method a required rule is scoped to (e.g. required_create)
+	scope methodType
+}
+
+// get the parsed fields of the struct type, building
+// and caching them on first use
+func (v *validator) structPlan(structType reflect.Type) []fieldPlan {
+	if plan, ok := v.plans.Load(structType); ok {
+		return plan.([]fieldPlan)
+	}
+
+	fields := make([]fieldPlan, 0, structType.NumField())
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		tag := field.Tag.Get("firevault")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		rules := v.parseTag(tag)
+
+		// use first tag rule as new field name, if not empty
+		name := field.Name
+		if rules[0] != "" {
+			name = rules[0]
+		}
+
+		fields = append(fields, fieldPlan{i, name, field.Name, v.newRulePlan(rules[1:])})
+	}
+
+	plan, _ := v.plans.LoadOrStore(structType, fields)
+	return plan.([]fieldPlan)
+}
+
+// clear the cached plans, as the rules' functions have changed
+func (v *validator) clearPlans() {
+	v.plans.Range(func(key, _ interface{}) bool {
+		v.plans.Delete(key)
+		return true
+	})
+}
+
+// parse the rules, separating the ones after a dive rule
+func (v *validator) newRulePlan(rules []string) *rulePlan {
+	rules, elemRules, dive := v.splitDiveRules(rules)
+
+	plan := &rulePlan{rules: rules}
+
+	for _, rule := range rules {
+		switch {
+		case rule == "omitempty":
+			plan.omitEmpty = true
+		case rule == string("omitempty_"+create):
+			plan.omitEmptyMethods = append(plan.omitEmptyMethods, create)
+		case rule == string("omitempty_"+update):
+			plan.omitEmptyMethods = append(plan.omitEmptyMethods, update)
+		case rule == string("omitempty_"+validate):
+			plan.omitEmptyMethods = append(plan.omitEmptyMethods, validate)
+		}
+	}
+
+	// remove omitempty, default and transform tags from rules, so no validation is attempted
+	for _, rule := range v.cleanRules(rules) {
+		plan.parsed = append(plan.parsed, v.parseRule(rule))
+	}
+
+	if dive {
+		plan.elems = v.newDivePlan(elemRules)
+	}
+
+	return plan
+}
+
+// parse the rules after a dive rule, separating the map key
+// rules (between keys and endkeys) from the rest
+func (v *validator) newDivePlan(rules []string) *rulePlan {
+	if len(rules) == 0 || rules[0] != "keys" {
+		return v.newRulePlan(rules)
+	}
+
+	end := slices.Index(rules, "endkeys")
+	if end == -1 {
+		return &rulePlan{invalid: "keys rule must be followed by an endkeys rule"}
+	}
+
+	plan := v.newRulePlan(rules[end+1:])
+	plan.keys = v.newRulePlan(rules[1:end])
+
+	return plan
+}
+
+// parse the rule's name and param, and resolve its function
+// (which is nil if it's not registered)
+func (v *validator) parseRule(rule string) parsedRule {
+	parsed := parsedRule{tag: rule}
+
+	if transName, ok := strings.CutPrefix(rule, "transform="); ok {
+		parsed.transform = true
+		parsed.name, parsed.param, _ = strings.Cut(transName, "=")
+		parsed.transformation = v.transformations[parsed.name]
+
+		return parsed
+	}
+
+	parsed.name, parsed.param, _ = strings.Cut(rule, "=")
+	parsed.validation = v.validations[parsed.name]
+	parsed.required = rule == "required" || isCrossFieldRequiredRule(rule)
+
+	if isMethodRequiredRule(rule) {
+		parsed.required = true
+		parsed.scope = methodType(strings.TrimPrefix(rule, "required_"))
+	}
+
+	return parsed
+}
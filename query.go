@@ -1,5 +1,7 @@
 package firevault
 
+import "cloud.google.com/go/firestore"
+
 // A Firevault Query helps to filter and order
 // Firestore documents.
 //
@@ -7,7 +9,8 @@ package firevault
 // a new Query - it does not modify the old.
 type Query struct {
 	ids         []string
-	filters     []filter
+	filters     []Filter
+	selects     []string
 	orders      []order
 	startAt     []interface{}
 	startAfter  []interface{}
@@ -18,13 +21,28 @@ type Query struct {
 	offset      int
 }
 
-// represents a single filter in a Query
+// A Firevault Filter represents a condition which Firestore
+// documents must satisfy. Filters can be combined (and nested)
+// using the Or and And functions.
+//
+// To create a Filter, use the NewFilter, Or or And functions.
+type Filter interface {
+	entityFilter() firestore.EntityFilter
+}
+
+// represents a single field filter in a Query
 type filter struct {
 	path     string
 	operator string
 	value    interface{}
 }
 
+// represents multiple filters, combined by an operator
+type compositeFilter struct {
+	or      bool
+	filters []Filter
+}
+
 // represents a single order in a Query
 type order struct {
 	path      string
@@ -44,6 +62,32 @@ const Desc Direction = Direction(2)
 // ID of a document in queries.
 const DocumentID = "__name__"
 
+// Create a new Filter instance, which filters documents
+// based on the value of a single field.
+//
+// The path argument can be a single field or a dot-separated
+// sequence of fields, and must not contain any of
+// the runes "˜*/[]".
+//
+// The operator argument must be one of "==", "!=", "<", "<=",
+// ">", ">=", "array-contains", "array-contains-any", "in" or
+// "not-in".
+func NewFilter(path string, operator string, value interface{}) Filter {
+	return filter{path, operator, value}
+}
+
+// Or returns a Filter which is satisfied when at least one
+// of the provided filters is satisfied.
+func Or(filters ...Filter) Filter {
+	return compositeFilter{true, filters}
+}
+
+// And returns a Filter which is satisfied when all of the
+// provided filters are satisfied.
+func And(filters ...Filter) Filter {
+	return compositeFilter{false, filters}
+}
+
 // Create a new Query instance.
 //
 // A Firevault Query helps to filter and order
@@ -87,6 +131,57 @@ func (q Query) Where(path string, operator string, value interface{}) Query {
 	return q
 }
 
+// WhereFilter returns a new Query that filters the set of
+// results using the provided Filter, which can be a nested
+// combination of filters (created with the NewFilter, Or
+// and And functions).
+//
+// It can be combined with the Where method. All filters must
+// be satisfied for a document to be returned.
+func (q Query) WhereFilter(f Filter) Query {
+	q.filters = append(q.filters, f)
+	return q
+}
+
+// Or returns a new Query that filters the set of results,
+// returning documents which satisfy at least one of the
+// provided filters.
+//
+// It is a shorthand for calling WhereFilter with the Or function.
+func (q Query) Or(filters ...Filter) Query {
+	return q.WhereFilter(Or(filters...))
+}
+
+// And returns a new Query that filters the set of results,
+// returning documents which satisfy all of the provided
+// filters.
+//
+// It is a shorthand for calling WhereFilter with the And function.
+func (q Query) And(filters ...Filter) Query {
+	return q.WhereFilter(And(filters...))
+}
+
+// Select returns a new Query that specifies the field paths
+// to return from the result documents. Fields which are not
+// selected are left at their zero value in the decoded data.
+// To check which fields were returned, use Document's Loaded
+// method.
+//
+// The paths can be single fields or dot-separated sequences
+// of fields, and must not contain any of the runes "˜*/[]".
+//
+// Calling Select with no paths returns documents without
+// any fields (i.e. only their IDs and metadata).
+//
+// Select is ignored for queries which use the ID method,
+// as the whole documents are fetched directly.
+//
+// Calling Select overrides a previous call to the method.
+func (q Query) Select(paths ...string) Query {
+	q.selects = append([]string{}, paths...)
+	return q
+}
+
 // OrderBy returns a new Query that specifies the order in which
 // results are returned. A Query can have multiple OrderBy
 // specifications. It appends the specification to the list of
@@ -113,6 +208,13 @@ func (q Query) OrderBy(path string, direction Direction) Query {
 // the corresponding value should be the document ID relative
 // to the query's collection.
 //
+// Alternatively, StartAt can be called with a single Document
+// (of any type). If it was fetched, its snapshot is used, which
+// follows Firestore's snapshot cursor semantics (i.e. results
+// are ordered by DocumentID as well). Otherwise, its values for
+// each OrderBy clause are used (by field name), with its Path
+// (or its ID, outside collection groups) used for DocumentID.
+//
 // Calling StartAt overrides a previous call to StartAt or
 // StartAfter.
 func (q Query) StartAt(values ...interface{}) Query {
@@ -130,6 +232,13 @@ func (q Query) StartAt(values ...interface{}) Query {
 // the corresponding value should be the document ID relative
 // to the query's collection.
 //
+// Alternatively, StartAfter can be called with a single Document
+// (of any type). If it was fetched, its snapshot is used, which
+// follows Firestore's snapshot cursor semantics (i.e. results
+// are ordered by DocumentID as well). Otherwise, its values for
+// each OrderBy clause are used (by field name), with its Path
+// (or its ID, outside collection groups) used for DocumentID.
+//
 // Calling StartAfter overrides a previous call to StartAt or
 // StartAfter.
 func (q Query) StartAfter(values ...interface{}) Query {
@@ -147,6 +256,13 @@ func (q Query) StartAfter(values ...interface{}) Query {
 // the corresponding value should be the document ID relative
 // to the query's collection.
 //
+// Alternatively, EndBefore can be called with a single Document
+// (of any type). If it was fetched, its snapshot is used, which
+// follows Firestore's snapshot cursor semantics (i.e. results
+// are ordered by DocumentID as well). Otherwise, its values for
+// each OrderBy clause are used (by field name), with its Path
+// (or its ID, outside collection groups) used for DocumentID.
+//
 // Calling EndBefore overrides a previous call to EndAt or
 // EndBefore.
 func (q Query) EndBefore(values ...interface{}) Query {
@@ -164,6 +280,13 @@ func (q Query) EndBefore(values ...interface{}) Query {
 // the corresponding value should be the document ID relative
 // to the query's collection.
 //
+// Alternatively, EndAt can be called with a single Document
+// (of any type). If it was fetched, its snapshot is used, which
+// follows Firestore's snapshot cursor semantics (i.e. results
+// are ordered by DocumentID as well). Otherwise, its values for
+// each OrderBy clause are used (by field name), with its Path
+// (or its ID, outside collection groups) used for DocumentID.
+//
 // Calling EndAt overrides a previous call to EndAt or
 // EndBefore.
 func (q Query) EndAt(values ...interface{}) Query {
@@ -191,3 +314,24 @@ func (q Query) Offset(num int) Query {
 	q.offset = num
 	return q
 }
+
+// convert the filter to a firestore property filter
+func (f filter) entityFilter() firestore.EntityFilter {
+	return firestore.PropertyFilter{Path: f.path, Operator: f.operator, Value: f.value}
+}
+
+// convert the filter (and all nested filters) to a firestore
+// composite filter
+func (f compositeFilter) entityFilter() firestore.EntityFilter {
+	filters := make([]firestore.EntityFilter, 0, len(f.filters))
+
+	for _, nested := range f.filters {
+		filters = append(filters, nested.entityFilter())
+	}
+
+	if f.or {
+		return firestore.OrFilter{Filters: filters}
+	}
+
+	return firestore.AndFilter{Filters: filters}
+}
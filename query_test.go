@@ -0,0 +1,185 @@
+package firevault
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+)
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   firestore.EntityFilter
+	}{
+		{
+			name:   "Field filter",
+			filter: NewFilter("age", ">=", 18),
+			want:   firestore.PropertyFilter{Path: "age", Operator: ">=", Value: 18},
+		},
+		{
+			name:   "Or filter",
+			filter: Or(NewFilter("age", "<", 18), NewFilter("age", ">", 65)),
+			want: firestore.OrFilter{Filters: []firestore.EntityFilter{
+				firestore.PropertyFilter{Path: "age", Operator: "<", Value: 18},
+				firestore.PropertyFilter{Path: "age", Operator: ">", Value: 65},
+			}},
+		},
+		{
+			name: "Nested filters",
+			filter: And(
+				NewFilter("verified", "==", true),
+				Or(NewFilter("role", "==", "admin"), NewFilter("role", "==", "editor")),
+			),
+			want: firestore.AndFilter{Filters: []firestore.EntityFilter{
+				firestore.PropertyFilter{Path: "verified", Operator: "==", Value: true},
+				firestore.OrFilter{Filters: []firestore.EntityFilter{
+					firestore.PropertyFilter{Path: "role", Operator: "==", Value: "admin"},
+					firestore.PropertyFilter{Path: "role", Operator: "==", Value: "editor"},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.entityFilter()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter.entityFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	base := NewQuery().Where("age", ">=", 18)
+	orQuery := base.Or(NewFilter("role", "==", "admin"), NewFilter("role", "==", "editor"))
+
+	if len(base.filters) != 1 {
+		t.Errorf("Expected original Query to be unchanged, got %d filters", len(base.filters))
+	}
+	if len(orQuery.filters) != 2 {
+		t.Fatalf("Expected 2 filters, got %d", len(orQuery.filters))
+	}
+	if _, ok := orQuery.filters[1].entityFilter().(firestore.OrFilter); !ok {
+		t.Errorf("Expected second filter to be an OrFilter, got %T", orQuery.filters[1].entityFilter())
+	}
+}
+
+func TestQuerySelect(t *testing.T) {
+	paths := []string{"name", "email"}
+	query := NewQuery().Select(paths...)
+
+	paths[0] = "age"
+	if !reflect.DeepEqual(query.selects, []string{"name", "email"}) {
+		t.Errorf("Expected selects to be copied, got %v", query.selects)
+	}
+
+	if NewQuery().selects != nil {
+		t.Error("Expected new Query to select all fields")
+	}
+	if NewQuery().Select().selects == nil {
+		t.Error("Expected Select with no paths to select no fields")
+	}
+}
+
+func TestDocumentCursor(t *testing.T) {
+	type Address struct {
+		City string `firevault:"city"`
+	}
+
+	type User struct {
+		Name    string            `firevault:"name"`
+		Age     int               `firevault:"age,min=18"`
+		Address *Address          `firevault:"address"`
+		Meta    map[string]string `firevault:"meta"`
+	}
+
+	v := newValidator()
+
+	// resolve references like a collection group would, by path only
+	groupRef := func(_ string, path string) (*firestore.DocumentRef, error) {
+		if path == "" {
+			return nil, errors.New("no path")
+		}
+
+		return &firestore.DocumentRef{Path: path}, nil
+	}
+
+	doc := Document[User]{
+		ID:   "abc",
+		Path: "teams/core/users/abc",
+		Data: User{
+			Name:    "Bobby Donev",
+			Age:     26,
+			Address: &Address{City: "London"},
+			Meta:    map[string]string{"team": "core"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		orders  []order
+		want    []interface{}
+		wantErr bool
+	}{
+		{
+			name:   "Field orders",
+			orders: []order{{"age", Desc}, {"name", Asc}},
+			want:   []interface{}{26, "Bobby Donev"},
+		},
+		{
+			name:   "Nested fields and ID",
+			orders: []order{{"address.city", Asc}, {"meta.team", Asc}, {DocumentID, Asc}},
+			want: []interface{}{
+				"London",
+				"core",
+				&firestore.DocumentRef{Path: "teams/core/users/abc"},
+			},
+		},
+		{
+			name:    "Unknown field",
+			orders:  []order{{"email", Asc}},
+			wantErr: true,
+		},
+		{
+			name:    "No orders",
+			orders:  nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.cursor(v, groupRef, tt.orders)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.cursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document.cursor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	t.Run("Collection references", func(t *testing.T) {
+		users := &firestore.CollectionRef{ID: "users", Path: "projects/p/databases/d/documents/users"}
+
+		docRef, err := (&CollectionRef[User]{ref: users}).cursorRef("abc", "")
+		if err != nil || docRef.Path != users.Path+"/abc" {
+			t.Errorf("Expected reference by ID, got %v (error %v)", docRef, err)
+		}
+
+		_, err = (&CollectionRef[User]{}).cursorRef("abc", "")
+		if err == nil {
+			t.Error("Expected error for collection group document without a path")
+		}
+	})
+
+	t.Run("No path", func(t *testing.T) {
+		_, err := Document[User]{ID: "abc"}.cursor(v, groupRef, []order{{DocumentID, Asc}})
+		if err == nil {
+			t.Error("Expected error for collection group document without a path")
+		}
+	})
+}
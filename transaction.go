@@ -0,0 +1,182 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/firestore"
+)
+
+// A Firevault Transaction allows for the atomic reading
+// and writing of documents, across multiple collections.
+//
+// A Transaction is passed to the function given to
+// Connection's RunTransaction method and must not be
+// used outside of it.
+type Transaction struct {
+	connection *Connection
+	tx         *firestore.Transaction
+}
+
+// A Firevault TxCollectionRef holds a reference to a
+// Firestore Collection and allows for the fetching and
+// modifying (with validation) of documents in it, as part
+// of a Transaction.
+type TxCollectionRef[T interface{}] struct {
+	collection *CollectionRef[T]
+	tx         *Transaction
+}
+
+// RunTransaction runs fn in a Transaction. All reads and
+// writes in fn should be made through CollectionRefs bound to
+// the given Transaction (using the WithTransaction method).
+//
+// If fn returns nil, the Transaction is committed. If the
+// commit fails due to contention with another transaction,
+// fn is retried (up to 5 attempts in total), so it should
+// be safe to call multiple times.
+//
+// If fn returns an error, the Transaction is rolled back and
+// the same error is returned.
+//
+// All reads must be made before any writes.
+func (c *Connection) RunTransaction(ctx context.Context, fn func(tx *Transaction) error) error {
+	if c == nil || c.client == nil {
+		return errors.New("firevault: nil Connection or Firestore Client")
+	}
+
+	err := c.client.RunTransaction(ctx, func(_ context.Context, tx *firestore.Transaction) error {
+		return fn(&Transaction{c, tx})
+	})
+
+	return wrapError(err)
+}
+
+// WithTransaction returns a TxCollectionRef, which performs
+// all reads and writes as part of the provided Transaction.
+//
+// Returns nil if the Transaction was created by a different
+// Connection.
+func (c *CollectionRef[T]) WithTransaction(tx *Transaction) *TxCollectionRef[T] {
+	if c == nil || tx == nil || tx.connection != c.connection {
+		return nil
+	}
+
+	return &TxCollectionRef[T]{c, tx}
+}
+
+// Create a Firestore document with provided data (after validation),
+// as part of the Transaction.
+//
+// If a custom ID is specified (using Options), and a document
+// with that ID already exists, the Transaction fails with
+// ErrAlreadyExists.
+func (t *TxCollectionRef[T]) Create(ctx context.Context, data *T, opts ...Options) (string, error) {
+	if t == nil {
+		return "", errors.New("firevault: nil TxCollectionRef")
+	}
+
+	valOptions, id, _ := t.collection.parseOptions(create, opts...)
+
+	dataMap, err := t.collection.connection.validator.validate(ctx, data, valOptions)
+	if err != nil {
+		return "", err
+	}
+
+	docRef := t.collection.ref.NewDoc()
+	if id != "" {
+		docRef = t.collection.ref.Doc(id)
+	}
+
+	err = t.tx.tx.Create(docRef, dataMap)
+	if err != nil {
+		return "", err
+	}
+
+	return docRef.ID, nil
+}
+
+// Update all Firestore documents which match provided Query
+// (after data validation), as part of the Transaction.
+//
+// If the Query doesn't specify IDs, the matching documents are
+// read first, so it must be called before any other writes.
+//
+// If any of the documents do not exist, the Transaction fails
+// with ErrNotFound. If any of them have changed since the version
+// specified with the IfUnchangedSince or IfMatch options, the
+// Transaction fails with ErrFailedPrecondition.
+func (t *TxCollectionRef[T]) Update(ctx context.Context, query Query, data *T, opts ...Options) error {
+	if t == nil {
+		return errors.New("firevault: nil TxCollectionRef")
+	}
+
+	updates, err := t.collection.prepareUpdates(ctx, data, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return t.writeAll(ctx, query, func(docRef *firestore.DocumentRef) error {
+		return t.tx.tx.Update(docRef, updates, t.collection.preconditions(docRef, opts...)...)
+	})
+}
+
+// Delete all Firestore documents which match provided Query,
+// as part of the Transaction.
+//
+// If the Query doesn't specify IDs, the matching documents are
+// read first, so it must be called before any other writes.
+func (t *TxCollectionRef[T]) Delete(ctx context.Context, query Query, opts ...Options) error {
+	if t == nil {
+		return errors.New("firevault: nil TxCollectionRef")
+	}
+
+	return t.writeAll(ctx, query, func(docRef *firestore.DocumentRef) error {
+		return t.tx.tx.Delete(docRef, t.collection.preconditions(docRef, opts...)...)
+	})
+}
+
+// Find all Firestore documents which match provided Query,
+// as part of the Transaction.
+//
+// If the Query specifies IDs of documents which do not exist,
+// an error wrapping ErrNotFound is returned.
+func (t *TxCollectionRef[T]) Find(ctx context.Context, query Query) ([]Document[T], error) {
+	if t == nil {
+		return nil, errors.New("firevault: nil TxCollectionRef")
+	}
+
+	return t.collection.find(ctx, query, t.tx.tx)
+}
+
+// Find the first Firestore document which matches provided Query,
+// as part of the Transaction.
+//
+// If no document matches the Query, ErrNotFound is returned.
+func (t *TxCollectionRef[T]) FindOne(ctx context.Context, query Query) (Document[T], error) {
+	if t == nil {
+		return Document[T]{}, errors.New("firevault: nil TxCollectionRef")
+	}
+
+	return t.collection.findOne(ctx, query, t.tx.tx)
+}
+
+// perform a write on every document which matches the query
+func (t *TxCollectionRef[T]) writeAll(
+	ctx context.Context,
+	query Query,
+	operation func(*firestore.DocumentRef) error,
+) error {
+	return t.collection.forEachRef(ctx, query, t.tx.tx, func(docRef *firestore.DocumentRef) error {
+		err := operation(docRef)
+		if err != nil {
+			return docError(err, docRef.ID)
+		}
+
+		return nil
+	})
+}
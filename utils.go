@@ -2,6 +2,7 @@ package firevault
 
 import (
 	"errors"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,9 +28,19 @@ func asUint(param string) (uint64, error) {
 	return u, nil
 }
 
+// asBool returns the parameter as a bool, or error if it can't convert
+func asBool(param string) (bool, error) {
+	b, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, errors.New("firevault: " + err.Error())
+	}
+
+	return b, nil
+}
+
 // asFloat returns the parameter as a float64, or error if it can't convert
 func asFloat(param string) (float64, error) {
-	f, err := strconv.ParseFloat(param, 32)
+	f, err := strconv.ParseFloat(param, 64)
 	if err != nil {
 		return 0, errors.New("firevault: " + err.Error())
 	}
@@ -51,3 +62,34 @@ func asTime(param string) (time.Time, error) {
 
 	return t, nil
 }
+
+// relativePath strips the "projects/{p}/databases/{d}/documents/" prefix
+// from a Firestore resource name
+func relativePath(path string) string {
+	parts := strings.SplitN(path, "/", 6)
+	if len(parts) < 6 || parts[0] != "projects" || parts[2] != "databases" || parts[4] != "documents" {
+		return path
+	}
+
+	return parts[5]
+}
+
+// subCollectionPath joins the document's path and the subcollection's
+// name, or returns an error if the result isn't a valid collection path
+func subCollectionPath(docPath string, name string) (string, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return "", errors.New("firevault: invalid subcollection name - " + name)
+	}
+
+	segments := strings.Split(docPath, "/")
+	if len(segments)%2 != 0 || slices.Contains(segments, "") {
+		return "", errors.New("firevault: invalid document path - " + docPath)
+	}
+
+	return docPath + "/" + name, nil
+}
+
+// lastPathSegment returns the last segment of a dot-separated path
+func lastPathSegment(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
@@ -0,0 +1,52 @@
+package firevault
+
+import "testing"
+
+func TestRelativePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"Document path", "projects/p/databases/(default)/documents/users/abc", "users/abc"},
+		{"Collection named documents", "projects/documents/databases/d/documents/documents/abc", "documents/abc"},
+		{"Already relative", "users/abc", "users/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relativePath(tt.path); got != tt.want {
+				t.Errorf("relativePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubCollectionPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		docPath string
+		subName string
+		want    string
+		wantErr bool
+	}{
+		{"Valid subcollection", "users/abc", "orders", "users/abc/orders", false},
+		{"Valid nested subcollection", "users/abc/orders/xyz", "items", "users/abc/orders/xyz/items", false},
+		{"Collection path", "users", "orders", "", true},
+		{"Empty segment", "users//abc", "orders", "", true},
+		{"Empty name", "users/abc", "", "", true},
+		{"Name with slash", "users/abc", "orders/xyz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := subCollectionPath(tt.docPath, tt.subName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("subCollectionPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("subCollectionPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"cloud.google.com/go/firestore"
 )
 
 // A ValidationFn is the function that's executed
@@ -18,13 +22,38 @@ type ValidationFn func(ctx context.Context, path string, value reflect.Value, pa
 // during a transformation.
 type TransformationFn func(ctx context.Context, path string, value reflect.Value) (interface{}, error)
 
+// a transformation which also receives its param (e.g. truncate=10);
+// registered transformations ignore it
+type transformationFn func(ctx context.Context, path string, value reflect.Value, param string) (interface{}, error)
+
+// A StructValidationFn is the function that's executed
+// during a struct-level validation.
+type StructValidationFn func(ctx context.Context, method string, value reflect.Value) error
+
+// A Validatable struct validates itself, after its fields'
+// rules have passed, by implementing the Validate method.
+//
+// The method is one of "create", "update" or "validate".
+type Validatable interface {
+	Validate(ctx context.Context, method string) error
+}
+
 type validator struct {
-	validations     map[string]ValidationFn
-	transformations map[string]TransformationFn
+	validations       map[string]ValidationFn
+	transformations   map[string]transformationFn
+	structValidations map[reflect.Type]StructValidationFn
+	regexes           map[string]*regexp.Regexp
+	// the parsed fields of each struct type (reflect.Type -> []fieldPlan)
+	plans sync.Map
 }
 
 func newValidator() *validator {
-	validator := &validator{make(map[string]ValidationFn), make(map[string]TransformationFn)}
+	validator := &validator{
+		validations:       make(map[string]ValidationFn),
+		transformations:   make(map[string]transformationFn),
+		structValidations: make(map[reflect.Type]StructValidationFn),
+		regexes:           make(map[string]*regexp.Regexp),
+	}
 
 	// Register predefined validators
 	for k, v := range builtInValidators {
@@ -32,6 +61,19 @@ func newValidator() *validator {
 		_ = validator.registerValidation(k, v)
 	}
 
+	// Register predefined transformations
+	for k, t := range builtInTransformations {
+		validator.transformations[k] = t
+	}
+
+	// Register the regex validator, which uses the registered regexes
+	_ = validator.registerValidation("regex", validator.validateRegex)
+
+	// Register predefined cross-field validators
+	for k, v := range validator.crossFieldValidators() {
+		_ = validator.registerValidation(k, v)
+	}
+
 	return validator
 }
 
@@ -50,6 +92,10 @@ func (v *validator) registerValidation(name string, validation ValidationFn) err
 	}
 
 	v.validations[name] = validation
+
+	// cached plans hold the previous function (if any)
+	v.clearPlans()
+
 	return nil
 }
 
@@ -67,7 +113,73 @@ func (v *validator) registerTransformation(name string, transformation Transform
 		return fmt.Errorf("firevault: transformation function %s cannot be empty", name)
 	}
 
-	v.transformations[name] = transformation
+	v.transformations[name] = func(ctx context.Context, path string, value reflect.Value, _ string) (interface{}, error) {
+		return transformation(ctx, path, value)
+	}
+
+	// cached plans hold the previous function (if any)
+	v.clearPlans()
+
+	return nil
+}
+
+// register a named regex, for use with the regex rule
+func (v *validator) registerRegex(name string, pattern string) error {
+	if v == nil {
+		return errors.New("firevault: nil validator")
+	}
+
+	if len(name) == 0 {
+		return errors.New("firevault: regex name cannot be empty")
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("firevault: invalid regex %s - %s", name, err.Error())
+	}
+
+	v.regexes[name] = regex
+	return nil
+}
+
+// validates if field matches the regex registered with param's name
+func (v *validator) validateRegex(
+	_ context.Context,
+	fieldPath string,
+	fieldValue reflect.Value,
+	param string,
+) (bool, error) {
+	regex, ok := v.regexes[param]
+	if !ok {
+		return false, fmt.Errorf("firevault: unknown regex %s - %s", param, fieldPath)
+	}
+
+	return matchRegex(regex, fieldPath, fieldValue)
+}
+
+// register a struct-level validation
+func (v *validator) registerStructValidation(structType reflect.Type, validation StructValidationFn) error {
+	if v == nil {
+		return errors.New("firevault: nil validator")
+	}
+
+	if structType == nil {
+		return errors.New("firevault: struct validation type cannot be empty")
+	}
+
+	for structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("firevault: struct validation type %s must be a struct", structType)
+	}
+
+	if validation == nil {
+		return fmt.Errorf("firevault: struct validation function for %s cannot be empty", structType)
+	}
+
+	v.structValidations[structType] = validation
 	return nil
 }
 
@@ -100,7 +212,10 @@ func (v *validator) validate(
 		return nil, errors.New("firevault: data must be a pointer to a struct")
 	}
 
-	dataMap, err := v.validateFields(ctx, rs, "", opts)
+	// expose the struct to validations
+	ctx = context.WithValue(ctx, rootStructKey, rs.values)
+
+	dataMap, err := v.validateFields(ctx, rs, "", "", opts)
 	return dataMap, err
 }
 
@@ -110,32 +225,26 @@ func (v *validator) validateFields(
 	ctx context.Context,
 	rs reflectedStruct,
 	path string,
+	structPath string,
 	opts validationOpts,
 ) (map[string]interface{}, error) {
 	// map which will hold all fields to pass to firestore
 	dataMap := make(map[string]interface{})
 
-	// iterate over struct fields
-	for i := 0; i < rs.values.NumField(); i++ {
-		fieldValue := rs.values.Field(i)
-		fieldType := rs.types.Field(i)
-		fieldName := fieldType.Name
-
-		tag := fieldType.Tag.Get("firevault")
+	// expose the fields' parent struct to validations
+	ctx = context.WithValue(ctx, parentStructKey, rs.values)
 
-		if tag == "" || tag == "-" {
-			continue
-		}
-
-		rules := v.parseTag(tag)
+	// failed validations, if all errors should be collected
+	var errs ValidationErrors
 
-		// use first tag rule as new field name, if not empty
-		if rules[0] != "" {
-			fieldName = rules[0]
-		}
+	// iterate over the struct's tagged fields
+	for _, field := range v.structPlan(rs.types) {
+		fieldValue := rs.values.Field(field.index)
+		fieldName := field.name
 
-		// get dot-separated field path
+		// get dot-separated field paths
 		fieldPath := v.getFieldPath(path, fieldName)
+		structFieldPath := v.getFieldPath(structPath, field.structName)
 
 		// check if field is of supported type
 		err := v.validateFieldType(fieldValue, fieldPath)
@@ -143,13 +252,33 @@ func (v *validator) validateFields(
 			return nil, err
 		}
 
-		// check if field should be skipped based on provided tags
-		if v.shouldSkipField(fieldValue, fieldPath, rules, opts) {
+		// get field transform (e.g. increment) for the current method
+		transform, err := v.getFieldTransform(fieldValue, fieldPath, field.rules, opts.method)
+		if err != nil {
+			return nil, err
+		}
+
+		// server timestamps ignore the field's value
+		if transform == serverTimestamp {
+			dataMap[fieldName] = firestore.ServerTimestamp
 			continue
 		}
 
-		// remove omitempty tags from rules, so no validation is attempted
-		rules = v.cleanRules(rules)
+		// populate empty field with its default value (if any)
+		defaultValue, hasDefault, err := v.getDefaultValue(fieldValue, fieldPath, field.rules, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasDefault {
+			rs.values.Field(field.index).Set(defaultValue)
+			fieldValue = rs.values.Field(field.index)
+		}
+
+		// check if field should be skipped based on provided tags
+		if v.shouldSkipField(fieldValue, fieldPath, field.rulePlan, opts) {
+			continue
+		}
 
 		// get pointer value, only if it's not nil
 		if fieldValue.Kind() == reflect.Pointer || fieldValue.Kind() == reflect.Ptr {
@@ -165,34 +294,169 @@ func (v *validator) validateFields(
 				ctx,
 				fieldValue,
 				fieldPath,
+				structFieldPath,
 				fieldName,
-				fieldType.Name,
-				rules,
+				field.structName,
+				field.parsed,
 				opts.method,
 			)
 			if err != nil {
+				if v.collectError(&errs, err, opts) {
+					continue
+				}
+
 				return nil, err
 			}
 
-			// set original struct's field value if changed
+			// set original struct's field value (or pointed to value) if changed
 			if newFieldValue != fieldValue {
-				rs.values.Field(i).Set(newFieldValue)
+				if structField := rs.values.Field(field.index); structField.Kind() == reflect.Pointer && !structField.IsNil() {
+					structField.Elem().Set(newFieldValue)
+				} else {
+					structField.Set(newFieldValue)
+				}
+
 				fieldValue = newFieldValue
 			}
+
+			// apply rules to the field's elements (unless it's a nil pointer)
+			if field.elems != nil && fieldValue.Kind() != reflect.Pointer {
+				err := v.applyDiveRules(
+					ctx,
+					fieldValue,
+					fieldPath,
+					structFieldPath,
+					fieldName,
+					field.structName,
+					field.elems,
+					opts,
+				)
+				if err != nil {
+					if v.collectError(&errs, err, opts) {
+						continue
+					}
+
+					return nil, err
+				}
+			}
 		}
 
 		// get the final value to be added to the data map
-		finalValue, err := v.processFinalValue(ctx, fieldValue, fieldPath, opts)
+		finalValue, err := v.processFinalValue(ctx, fieldValue, fieldPath, structFieldPath, opts)
 		if err != nil {
+			if v.collectError(&errs, err, opts) {
+				continue
+			}
+
 			return nil, err
 		}
 
-		dataMap[fieldName] = finalValue
+		dataMap[fieldName] = v.applyFieldTransform(transform, finalValue)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// validate the struct as a whole, once all fields are valid
+	if !opts.skipValidation {
+		err := v.validateStruct(ctx, rs, path, structPath, opts)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return dataMap, nil
 }
 
+// run the struct-level validations (registered for the struct's type,
+// or implemented by it) and wrap their errors as field errors
+func (v *validator) validateStruct(
+	ctx context.Context,
+	rs reflectedStruct,
+	path string,
+	structPath string,
+	opts validationOpts,
+) error {
+	if !rs.values.CanInterface() {
+		return nil
+	}
+
+	var err error
+
+	if validation, ok := v.structValidations[rs.types]; ok {
+		err = validation(ctx, string(opts.method), rs.values)
+	}
+
+	if err == nil {
+		// use a pointer, so methods with a pointer receiver are found,
+		// copying values which aren't addressable (e.g. in maps)
+		ptr := reflect.New(rs.types)
+		if rs.values.CanAddr() {
+			ptr = rs.values.Addr()
+		} else {
+			ptr.Elem().Set(rs.values)
+		}
+
+		data := ptr.Interface()
+
+		if validatable, ok := data.(Validatable); ok {
+			err = validatable.Validate(ctx, string(opts.method))
+		}
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	// keep errors which already hold field details
+	var valErrs ValidationErrors
+	var fe FieldError
+	if errors.As(err, &valErrs) || errors.As(err, &fe) {
+		return err
+	}
+
+	fieldName, structFieldName := rs.types.Name(), rs.types.Name()
+	if path != "" {
+		fieldName, structFieldName = lastPathSegment(path), lastPathSegment(structPath)
+	}
+
+	return &fieldError{
+		code:        "failed-struct-validation",
+		tag:         "struct",
+		field:       fieldName,
+		structField: structFieldName,
+		path:        path,
+		structPath:  structPath,
+		value:       rs.values.Interface(),
+		kind:        rs.values.Kind(),
+		typ:         rs.types,
+		err:         err,
+	}
+}
+
+// add the error's field errors to errs, if all errors should
+// be collected, reporting whether it was a validation error
+func (v *validator) collectError(errs *ValidationErrors, err error, opts validationOpts) bool {
+	if !opts.collectAllErrors {
+		return false
+	}
+
+	var valErrs ValidationErrors
+	if errors.As(err, &valErrs) {
+		*errs = append(*errs, valErrs...)
+		return true
+	}
+
+	var fe FieldError
+	if errors.As(err, &fe) {
+		*errs = append(*errs, fe)
+		return true
+	}
+
+	return false
+}
+
 // get dot-separated field path
 func (v *validator) getFieldPath(path string, fieldName string) string {
 	if path == "" {
@@ -216,11 +480,10 @@ func (v *validator) validateFieldType(fieldValue reflect.Value, fieldPath string
 func (v *validator) shouldSkipField(
 	fieldValue reflect.Value,
 	fieldPath string,
-	rules []string,
+	plan *rulePlan,
 	opts validationOpts,
 ) bool {
-	omitEmptyMethodTag := string("omitempty_" + opts.method)
-	shouldOmitEmpty := slices.Contains(rules, "omitempty") || slices.Contains(rules, omitEmptyMethodTag)
+	shouldOmitEmpty := plan.omitEmpty || slices.Contains(plan.omitEmptyMethods, opts.method)
 
 	if shouldOmitEmpty && !slices.Contains(opts.emptyFieldsAllowed, fieldPath) {
 		return !hasValue(fieldValue)
@@ -229,13 +492,14 @@ func (v *validator) shouldSkipField(
 	return false
 }
 
-// remove omitempty tags from rules
+// remove omitempty, default and transform tags from rules
 func (v *validator) cleanRules(rules []string) []string {
 	cleanedRules := make([]string, 0, len(rules))
 
-	for index, rule := range rules {
-		if index != 0 && rule != "omitempty" && rule != string("omitempty_"+create) &&
-			rule != string("omitempty_"+update) && rule != string("omitempty_"+validate) {
+	for _, rule := range rules {
+		if rule != "omitempty" && rule != string("omitempty_"+create) &&
+			rule != string("omitempty_"+update) && rule != string("omitempty_"+validate) &&
+			!isFieldTransformRule(rule) && !isDefaultRule(rule) {
 			cleanedRules = append(cleanedRules, rule)
 		}
 	}
@@ -243,75 +507,577 @@ func (v *validator) cleanRules(rules []string) []string {
 	return cleanedRules
 }
 
+// get the field transform which applies to the method (if any),
+// and check the field is of a type supported by it
+func (v *validator) getFieldTransform(
+	fieldValue reflect.Value,
+	fieldPath string,
+	rules []string,
+	method methodType,
+) (fieldTransform, error) {
+	// transforms only apply when writing to firestore
+	if method != create && method != update {
+		return "", nil
+	}
+
+	for _, rule := range rules {
+		transform, scope, _ := strings.Cut(rule, "_")
+		if !isFieldTransformRule(rule) || (scope != "" && scope != string(method)) {
+			continue
+		}
+
+		err := validateFieldTransformType(fieldTransform(transform), fieldValue.Type(), fieldPath)
+		if err != nil {
+			return "", err
+		}
+
+		return fieldTransform(transform), nil
+	}
+
+	return "", nil
+}
+
+// get the default value of the field (preferring the one for the
+// current method), reporting whether the field should be set to it,
+// i.e. if it's empty and not allowed to be empty using options
+func (v *validator) getDefaultValue(
+	fieldValue reflect.Value,
+	fieldPath string,
+	rules []string,
+	opts validationOpts,
+) (reflect.Value, bool, error) {
+	if hasValue(fieldValue) || slices.Contains(opts.emptyFieldsAllowed, fieldPath) {
+		return reflect.Value{}, false, nil
+	}
+
+	param, found := "", false
+
+	for _, rule := range rules {
+		name, value, _ := strings.Cut(rule, "=")
+
+		if name == string("default_"+opts.method) {
+			param, found = value, true
+			break
+		}
+
+		if name == "default" && !found {
+			param, found = value, true
+		}
+	}
+
+	if !found {
+		return reflect.Value{}, false, nil
+	}
+
+	defaultValue, err := v.parseDefaultValue(fieldValue.Type(), fieldPath, param)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+
+	return defaultValue, true, nil
+}
+
+// parse the default value param, based on the field's type
+func (v *validator) parseDefaultValue(fieldType reflect.Type, fieldPath string, param string) (reflect.Value, error) {
+	// pointers are set to point to the default value
+	if fieldType.Kind() == reflect.Pointer {
+		elemValue, err := v.parseDefaultValue(fieldType.Elem(), fieldPath, param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		ptr := reflect.New(fieldType.Elem())
+		ptr.Elem().Set(elemValue)
+
+		return ptr, nil
+	}
+
+	value := reflect.New(fieldType).Elem()
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		value.SetString(param)
+	case reflect.Bool:
+		b, err := asBool(param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := asInt(param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if value.OverflowInt(i) {
+			return reflect.Value{}, errors.New("firevault: default value overflows field type - " + fieldPath)
+		}
+
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := asUint(param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if value.OverflowUint(u) {
+			return reflect.Value{}, errors.New("firevault: default value overflows field type - " + fieldPath)
+		}
+
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := asFloat(param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value.SetFloat(f)
+	case reflect.Struct:
+		timeType := reflect.TypeOf(time.Time{})
+
+		if !fieldType.ConvertibleTo(timeType) {
+			return reflect.Value{}, errors.New("firevault: default rule is not supported for field type - " + fieldPath)
+		}
+
+		t, err := asTime(param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value.Set(reflect.ValueOf(t).Convert(fieldType))
+	default:
+		return reflect.Value{}, errors.New("firevault: default rule is not supported for field type - " + fieldPath)
+	}
+
+	return value, nil
+}
+
+// wrap the final value in the firestore transform sentinel
+func (v *validator) applyFieldTransform(transform fieldTransform, value interface{}) interface{} {
+	elems, isSlice := value.([]interface{})
+
+	switch {
+	case transform == increment && value != nil:
+		number := reflect.ValueOf(value)
+
+		// convert named types (e.g. type Count int), which firestore
+		// doesn't accept, leaving nil pointers (with nothing to
+		// increment by) as they are
+		switch {
+		case number.CanInt():
+			return firestore.Increment(number.Int())
+		case number.CanUint():
+			return firestore.Increment(int64(number.Uint()))
+		case number.CanFloat():
+			return firestore.Increment(number.Float())
+		default:
+			return value
+		}
+	case transform == arrayUnion && isSlice:
+		return firestore.ArrayUnion(elems...)
+	case transform == arrayRemove && isSlice:
+		return firestore.ArrayRemove(elems...)
+	default:
+		return value
+	}
+}
+
+// check the field at the path supports the transform, and
+// that the transform's value(s) match the field's type
+func (v *validator) validateTransformValue(
+	structType reflect.Type,
+	path string,
+	transform fieldTransform,
+	value interface{},
+) error {
+	fieldType, err := v.getFieldType(structType, path)
+	if err != nil {
+		return err
+	}
+
+	err = validateFieldTransformType(transform, fieldType, path)
+	if err != nil {
+		return err
+	}
+
+	if transform == increment {
+		if value == nil || !isIncrementable(reflect.TypeOf(value)) {
+			return errors.New("firevault: increment value must be a number (excluding uint and uint64) - " + path)
+		}
+
+		return nil
+	}
+
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	elemType := fieldType.Elem()
+
+	for _, elem := range value.([]interface{}) {
+		if elem == nil {
+			return fmt.Errorf("firevault: %s value must be of type %s - %s", transform, elemType, path)
+		}
+
+		// numbers of any type are accepted for numeric elements
+		t := reflect.TypeOf(elem)
+		if !t.AssignableTo(elemType) && !(isNumeric(t) && isNumeric(elemType)) {
+			return fmt.Errorf("firevault: %s value must be of type %s - %s", transform, elemType, path)
+		}
+	}
+
+	return nil
+}
+
+// get the type of the field at the dot-separated path, using
+// the first tag rule as the field name
+func (v *validator) getFieldType(structType reflect.Type, path string) (reflect.Type, error) {
+	fieldType := structType
+
+	for _, name := range strings.Split(path, ".") {
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		switch fieldType.Kind() {
+		case reflect.Map:
+			fieldType = fieldType.Elem()
+			continue
+		case reflect.Struct:
+		default:
+			return nil, errors.New("firevault: invalid field path - " + path)
+		}
+
+		index, found := v.fieldIndex(fieldType, name)
+		if !found {
+			return nil, errors.New("firevault: unknown field - " + path)
+		}
+
+		fieldType = fieldType.Field(index).Type
+	}
+
+	return fieldType, nil
+}
+
+// get the value of the field at the dot-separated path, using
+// the first tag rule as the field name
+func (v *validator) getFieldValue(structValue reflect.Value, path string) (reflect.Value, error) {
+	fieldValue := structValue
+
+	for _, name := range strings.Split(path, ".") {
+		for fieldValue.Kind() == reflect.Pointer || fieldValue.Kind() == reflect.Interface {
+			if fieldValue.IsNil() {
+				return reflect.Value{}, errors.New("firevault: nil value in field path - " + path)
+			}
+
+			fieldValue = fieldValue.Elem()
+		}
+
+		switch fieldValue.Kind() {
+		case reflect.Map:
+			if fieldValue.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, errors.New("firevault: invalid field path - " + path)
+			}
+
+			fieldValue = fieldValue.MapIndex(reflect.ValueOf(name).Convert(fieldValue.Type().Key()))
+			if !fieldValue.IsValid() {
+				return reflect.Value{}, errors.New("firevault: unknown field - " + path)
+			}
+
+			continue
+		case reflect.Struct:
+		default:
+			return reflect.Value{}, errors.New("firevault: invalid field path - " + path)
+		}
+
+		index, found := v.fieldIndex(fieldValue.Type(), name)
+		if !found {
+			return reflect.Value{}, errors.New("firevault: unknown field - " + path)
+		}
+
+		fieldValue = fieldValue.Field(index)
+	}
+
+	return fieldValue, nil
+}
+
+// get the index of the struct field with the name, using the first
+// tag rule as the field name
+func (v *validator) fieldIndex(structType reflect.Type, name string) (int, bool) {
+	for _, field := range v.structPlan(structType) {
+		if field.name == name {
+			return field.index, true
+		}
+	}
+
+	return 0, false
+}
+
 // validate field based on rules
 func (v *validator) applyRules(
 	ctx context.Context,
 	fieldValue reflect.Value,
 	fieldPath string,
+	structPath string,
 	fieldName string,
 	structFieldName string,
-	rules []string,
+	rules []parsedRule,
 	method methodType,
 ) (reflect.Value, error) {
-	for _, rule := range rules {
-		// skip processing if the field is empty and it's not a required rule
-		requiredMethodTag := string("required" + method)
-		isRequiredRule := rule == "required" || rule == requiredMethodTag
-		if !hasValue(fieldValue) && !isRequiredRule {
-			continue
-		}
-
-		fe := &fieldError{
-			code:        "",
-			tag:         rule,
+	// create the rule's error, only once it has failed
+	newError := func(code string, rule parsedRule, param string) error {
+		return &fieldError{
+			code:        code,
+			tag:         rule.tag,
 			field:       fieldName,
 			structField: structFieldName,
+			path:        fieldPath,
+			structPath:  structPath,
 			value:       fieldValue.Interface(),
-			param:       "",
+			param:       param,
 			kind:        fieldValue.Kind(),
 			typ:         fieldValue.Type(),
 		}
+	}
 
-		if strings.HasPrefix(rule, "transform=") {
-			transName := strings.TrimPrefix(rule, "transform=")
+	for _, rule := range rules {
+		// skip required rules scoped to other methods
+		if rule.scope != "" && rule.scope != method {
+			continue
+		}
 
-			if transformation, ok := v.transformations[transName]; ok {
-				newValue, err := transformation(ctx, fieldPath, fieldValue)
-				if err != nil {
-					return reflect.Value{}, err
-				}
+		// skip processing if the field is empty and it's not a required rule
+		if !rule.required && !hasValue(fieldValue) {
+			continue
+		}
+
+		if rule.transform {
+			if rule.transformation == nil {
+				return reflect.Value{}, newError("unknown-transformation", rule, "")
+			}
+
+			newValue, err := rule.transformation(ctx, fieldPath, fieldValue, rule.param)
+			if err != nil {
+				return reflect.Value{}, err
+			}
 
-				// check if rule returned a new value and assign it
-				if newValue != nil {
-					fieldValue = reflect.ValueOf(newValue)
+			// check if rule returned a new value and assign it
+			if newValue != nil {
+				fieldValue = reflect.ValueOf(newValue)
+			}
+
+			continue
+		}
+
+		if rule.validation == nil {
+			return reflect.Value{}, newError("unknown-validation", rule, rule.param)
+		}
+
+		ok, err := rule.validation(ctx, fieldPath, fieldValue, rule.param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !ok {
+			return reflect.Value{}, newError("failed-validation", rule, rule.param)
+		}
+	}
+
+	return fieldValue, nil
+}
+
+// split the rules before the first dive rule from the ones
+// after it, reporting whether a dive rule is present
+func (v *validator) splitDiveRules(rules []string) ([]string, []string, bool) {
+	index := slices.Index(rules, "dive")
+	if index == -1 {
+		return rules, nil, false
+	}
+
+	return rules[:index], rules[index+1:], true
+}
+
+// apply the rules after a dive rule to each element of the slice,
+// array or map (and to map keys, if enclosed in keys and endkeys)
+func (v *validator) applyDiveRules(
+	ctx context.Context,
+	fieldValue reflect.Value,
+	fieldPath string,
+	structPath string,
+	fieldName string,
+	structFieldName string,
+	plan *rulePlan,
+	opts validationOpts,
+) error {
+	if plan.invalid != "" {
+		return errors.New("firevault: " + plan.invalid + " - " + fieldPath)
+	}
+
+	if plan.keys != nil && fieldValue.Kind() != reflect.Map {
+		return errors.New("firevault: keys rule can only be used on maps - " + fieldPath)
+	}
+
+	var errs ValidationErrors
+
+	switch fieldValue.Kind() {
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < fieldValue.Len(); i++ {
+			elemValue := fieldValue.Index(i)
+
+			newValue, err := v.applyElemRules(
+				ctx,
+				elemValue,
+				fmt.Sprintf("%s[%d]", fieldPath, i),
+				fmt.Sprintf("%s[%d]", structPath, i),
+				fmt.Sprintf("%s[%d]", fieldName, i),
+				fmt.Sprintf("%s[%d]", structFieldName, i),
+				plan,
+				opts,
+			)
+			if err != nil {
+				if v.collectError(&errs, err, opts) {
+					continue
 				}
-			} else {
-				fe.code = "unknown-transformation"
-				return reflect.Value{}, fe
+
+				return err
 			}
-		} else {
-			// get param value if present
-			rule, param, _ := strings.Cut(rule, "=")
 
-			if validation, ok := v.validations[rule]; ok {
-				ok, err := validation(ctx, fieldPath, fieldValue, param)
+			// arrays are only settable if their field is
+			if newValue != elemValue && elemValue.CanSet() {
+				elemValue.Set(newValue)
+			}
+		}
+	case reflect.Map:
+		keys := fieldValue.MapKeys()
+
+		// sort keys, so errors are always reported in the same order
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+
+		for _, key := range keys {
+			elemPath := fmt.Sprintf("%s.%v", fieldPath, key.Interface())
+			elemStructPath := fmt.Sprintf("%s.%v", structPath, key.Interface())
+			elemName := fmt.Sprintf("%s.%v", fieldName, key.Interface())
+			elemStructName := fmt.Sprintf("%s.%v", structFieldName, key.Interface())
+
+			newKey := key
+
+			if plan.keys != nil {
+				var err error
+
+				newKey, err = v.applyElemRules(
+					ctx,
+					key,
+					elemPath,
+					elemStructPath,
+					elemName,
+					elemStructName,
+					plan.keys,
+					opts,
+				)
 				if err != nil {
-					return reflect.Value{}, err
+					if v.collectError(&errs, err, opts) {
+						continue
+					}
+
+					return err
 				}
-				if !ok {
-					fe.code = "failed-validation"
-					fe.param = param
-					return reflect.Value{}, fe
+			}
+
+			elemValue := fieldValue.MapIndex(key)
+
+			newValue, err := v.applyElemRules(
+				ctx,
+				elemValue,
+				elemPath,
+				elemStructPath,
+				elemName,
+				elemStructName,
+				plan,
+				opts,
+			)
+			if err != nil {
+				if v.collectError(&errs, err, opts) {
+					continue
 				}
-			} else {
-				fe.code = "unknown-validation"
-				fe.param = param
-				return reflect.Value{}, fe
+
+				return err
+			}
+
+			// replace the element if its key or value has changed
+			if newKey != key {
+				fieldValue.SetMapIndex(key, reflect.Value{})
+			}
+
+			if newKey != key || newValue != elemValue {
+				fieldValue.SetMapIndex(newKey, newValue)
 			}
 		}
+	default:
+		return errors.New("firevault: dive rule can only be used on slices, arrays and maps - " + fieldPath)
 	}
 
-	return fieldValue, nil
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// apply the rules to a single element (diving further if
+// needed), returning the element's new value
+func (v *validator) applyElemRules(
+	ctx context.Context,
+	elemValue reflect.Value,
+	elemPath string,
+	structPath string,
+	elemName string,
+	structElemName string,
+	plan *rulePlan,
+	opts validationOpts,
+) (reflect.Value, error) {
+	if v.shouldSkipField(elemValue, elemPath, plan, opts) {
+		return elemValue, nil
+	}
+
+	// get pointer value, only if it's not nil
+	value := elemValue
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	newValue, err := v.applyRules(
+		ctx,
+		value,
+		elemPath,
+		structPath,
+		elemName,
+		structElemName,
+		plan.parsed,
+		opts.method,
+	)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if plan.elems != nil && newValue.Kind() != reflect.Pointer {
+		err := v.applyDiveRules(ctx, newValue, elemPath, structPath, elemName, structElemName, plan.elems, opts)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	// set the pointed to value, keeping the pointer
+	if elemValue.Kind() == reflect.Pointer && !elemValue.IsNil() {
+		if newValue != value {
+			elemValue.Elem().Set(newValue)
+		}
+
+		return elemValue, nil
+	}
+
+	return newValue, nil
 }
 
 // get final field value based on field's type
@@ -319,15 +1085,16 @@ func (v *validator) processFinalValue(
 	ctx context.Context,
 	fieldValue reflect.Value,
 	fieldPath string,
+	structPath string,
 	opts validationOpts,
 ) (interface{}, error) {
 	switch fieldValue.Kind() {
 	case reflect.Struct:
-		return v.processStructValue(ctx, fieldValue, fieldPath, opts)
+		return v.processStructValue(ctx, fieldValue, fieldPath, structPath, opts)
 	case reflect.Map:
-		return v.processMapValue(ctx, fieldValue, fieldPath, opts)
+		return v.processMapValue(ctx, fieldValue, fieldPath, structPath, opts)
 	case reflect.Array, reflect.Slice:
-		return v.processSliceValue(ctx, fieldValue, fieldPath, opts)
+		return v.processSliceValue(ctx, fieldValue, fieldPath, structPath, opts)
 	default:
 		return fieldValue.Interface(), nil
 	}
@@ -338,6 +1105,7 @@ func (v *validator) processStructValue(
 	ctx context.Context,
 	fieldValue reflect.Value,
 	fieldPath string,
+	structPath string,
 	opts validationOpts,
 ) (interface{}, error) {
 	// handle time.Time
@@ -349,6 +1117,7 @@ func (v *validator) processStructValue(
 		ctx,
 		reflectedStruct{fieldValue.Type(), fieldValue},
 		fieldPath,
+		structPath,
 		opts,
 	)
 }
@@ -358,25 +1127,51 @@ func (v *validator) processMapValue(
 	ctx context.Context,
 	fieldValue reflect.Value,
 	fieldPath string,
+	structPath string,
 	opts validationOpts,
 ) (interface{}, error) {
 	newMap := make(map[string]interface{})
 	iter := fieldValue.MapRange()
 
+	var errs ValidationErrors
+
 	for iter.Next() {
 		key := iter.Key()
 		val := iter.Value()
 
 		newFieldPath := fmt.Sprintf("%s.%v", fieldPath, key.Interface())
+		newStructPath := fmt.Sprintf("%s.%v", structPath, key.Interface())
+
+		// map values aren't addressable, so process a copy of structs and
+		// arrays (whose fields may be set by default and transform rules),
+		// as other kinds either refer to their elements or hold no fields
+		if val.Kind() == reflect.Struct || val.Kind() == reflect.Array {
+			valCopy := reflect.New(val.Type()).Elem()
+			valCopy.Set(val)
+			val = valCopy
+		}
 
-		processedValue, err := v.processFinalValue(ctx, val, newFieldPath, opts)
+		processedValue, err := v.processFinalValue(ctx, val, newFieldPath, newStructPath, opts)
 		if err != nil {
+			if v.collectError(&errs, err, opts) {
+				continue
+			}
+
 			return nil, err
 		}
 
+		// write the (possibly changed) copy back into the map
+		if val.CanAddr() {
+			fieldValue.SetMapIndex(key, val)
+		}
+
 		newMap[key.String()] = processedValue
 	}
 
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
 	return newMap, nil
 }
 
@@ -385,22 +1180,34 @@ func (v *validator) processSliceValue(
 	ctx context.Context,
 	fieldValue reflect.Value,
 	fieldPath string,
+	structPath string,
 	opts validationOpts,
 ) (interface{}, error) {
 	newSlice := make([]interface{}, fieldValue.Len())
 
+	var errs ValidationErrors
+
 	for i := 0; i < fieldValue.Len(); i++ {
 		val := fieldValue.Index(i)
 		newFieldPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+		newStructPath := fmt.Sprintf("%s[%d]", structPath, i)
 
-		processedValue, err := v.processFinalValue(ctx, val, newFieldPath, opts)
+		processedValue, err := v.processFinalValue(ctx, val, newFieldPath, newStructPath, opts)
 		if err != nil {
+			if v.collectError(&errs, err, opts) {
+				continue
+			}
+
 			return nil, err
 		}
 
 		newSlice[i] = processedValue
 	}
 
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
 	return newSlice, nil
 }
 
@@ -2,10 +2,13 @@ package firevault
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"cloud.google.com/go/firestore"
 )
 
 func TestValidate(t *testing.T) {
@@ -341,3 +344,1066 @@ func TestRegisterTransformation(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldTransforms(t *testing.T) {
+	v := newValidator()
+
+	type Stats struct {
+		Views int `firevault:"views"`
+	}
+
+	type TransformStruct struct {
+		Count     int       `firevault:"count,increment_update"`
+		Tags      []string  `firevault:"tags,omitempty,arrayUnion"`
+		UpdatedAt time.Time `firevault:"updated_at,serverTimestamp"`
+		Stats     Stats     `firevault:"stats,omitempty"`
+	}
+
+	type InvalidStruct struct {
+		Name string `firevault:"name,increment"`
+	}
+
+	data := &TransformStruct{Count: 2, Tags: []string{"a"}}
+
+	t.Run("Create", func(t *testing.T) {
+		result, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		want := map[string]interface{}{
+			"count":      2,
+			"tags":       firestore.ArrayUnion("a"),
+			"updated_at": firestore.ServerTimestamp,
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("validator.validate() = %v, want %v", result, want)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		result, err := v.validate(context.Background(), data, validationOpts{method: update})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(result["count"], firestore.Increment(2)) {
+			t.Errorf("Expected count to be incremented, got %v", result["count"])
+		}
+	})
+
+	t.Run("Nil pointer", func(t *testing.T) {
+		type PointerStruct struct {
+			Count *int `firevault:"count,increment"`
+			Views *int `firevault:"views,omitempty,increment"`
+		}
+
+		views := 3
+
+		result, err := v.validate(context.Background(), &PointerStruct{Views: &views}, validationOpts{method: update})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if count, ok := result["count"].(*int); !ok || count != nil {
+			t.Errorf("Expected nil count not to be incremented, got %v", result["count"])
+		}
+		if !reflect.DeepEqual(result["views"], firestore.Increment(3)) {
+			t.Errorf("Expected views to be incremented, got %v", result["views"])
+		}
+	})
+
+	t.Run("Unsupported field type", func(t *testing.T) {
+		_, err := v.validate(context.Background(), &InvalidStruct{Name: "a"}, validationOpts{method: create})
+		if err == nil {
+			t.Error("Expected error for increment on string field")
+		}
+	})
+
+	t.Run("Numeric types", func(t *testing.T) {
+		type Count int
+
+		type NumericStruct struct {
+			Count Count `firevault:"count,increment"`
+			Small uint8 `firevault:"small,increment"`
+		}
+
+		type Uint64Struct struct {
+			Total uint64 `firevault:"total,increment"`
+		}
+
+		result, err := v.validate(context.Background(), &NumericStruct{2, 3}, validationOpts{method: update})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// firestore only accepts built-in types it can store
+		if !reflect.DeepEqual(result["count"], firestore.Increment(int64(2))) {
+			t.Errorf("Expected named type to be converted, got %v", result["count"])
+		}
+		if !reflect.DeepEqual(result["small"], firestore.Increment(int64(3))) {
+			t.Errorf("Expected uint8 to be converted, got %v", result["small"])
+		}
+
+		_, err = v.validate(context.Background(), &Uint64Struct{1}, validationOpts{method: update})
+		if err == nil {
+			t.Error("Expected error for increment on uint64 field")
+		}
+
+		err = v.validateTransformValue(reflect.TypeFor[NumericStruct](), "count", increment, Count(1))
+		if err != nil {
+			t.Errorf("Unexpected error for named type value: %v", err)
+		}
+
+		err = v.validateTransformValue(reflect.TypeFor[NumericStruct](), "count", increment, uint64(1))
+		if err == nil {
+			t.Error("Expected error for uint64 value")
+		}
+
+		if got := v.applyFieldTransform(increment, Count(1)); !reflect.DeepEqual(got, firestore.Increment(int64(1))) {
+			t.Errorf("Expected named type value to be converted, got %v", got)
+		}
+	})
+
+	valueTests := []struct {
+		name      string
+		path      string
+		transform fieldTransform
+		value     interface{}
+		wantErr   bool
+	}{
+		{"Valid increment", "count", increment, 1, false},
+		{"Valid nested increment", "stats.views", increment, -1.5, false},
+		{"Invalid increment value", "count", increment, "1", true},
+		{"Invalid increment field", "updated_at", increment, 1, true},
+		{"Valid array union", "tags", arrayUnion, []interface{}{"b"}, false},
+		{"Invalid array union value", "tags", arrayRemove, []interface{}{1}, true},
+		{"Unknown field", "missing", increment, 1, true},
+	}
+
+	for _, tt := range valueTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.validateTransformValue(reflect.TypeFor[TransformStruct](), tt.path, tt.transform, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validator.validateTransformValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCollectAllErrors(t *testing.T) {
+	v := newValidator()
+
+	type Item struct {
+		Name string `firevault:"name,required"`
+	}
+
+	type Address struct {
+		City string `firevault:"city,required"`
+	}
+
+	type TestStruct struct {
+		Name    string          `firevault:"name,required,min=3"`
+		Email   string          `firevault:"email,email"`
+		Address Address         `firevault:"address"`
+		Items   []Item          `firevault:"items"`
+		Extra   map[string]Item `firevault:"extra"`
+	}
+
+	data := &TestStruct{
+		Name:    "Jo",
+		Email:   "not-an-email",
+		Address: Address{},
+		Items:   []Item{{"a"}, {}, {}},
+		Extra:   map[string]Item{"x": {}},
+	}
+
+	t.Run("First error only", func(t *testing.T) {
+		_, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if _, ok := err.(*fieldError); !ok {
+			t.Fatalf("Expected *fieldError, got %T", err)
+		}
+	})
+
+	t.Run("All errors", func(t *testing.T) {
+		_, err := v.validate(
+			context.Background(),
+			data,
+			validationOpts{method: create, collectAllErrors: true},
+		)
+
+		valErrs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("Expected ValidationErrors, got %T", err)
+		}
+
+		// name, email, address.city, items[1].name, items[2].name, extra.x.name
+		if len(valErrs) != 6 {
+			t.Fatalf("Expected 6 errors, got %d: %v", len(valErrs), err)
+		}
+
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Field() != "name" || fe.Tag() != "min=3" {
+			t.Errorf("Expected first error to be for name's min rule, got %v", fe)
+		}
+
+		if !strings.Contains(err.Error(), "'email' failed on the 'email' tag") {
+			t.Errorf("Expected error message to contain email error, got %q", err.Error())
+		}
+	})
+
+	t.Run("Valid data", func(t *testing.T) {
+		valid := &TestStruct{Name: "John", Address: Address{City: "London"}}
+
+		_, err := v.validate(
+			context.Background(),
+			valid,
+			validationOpts{method: create, collectAllErrors: true},
+		)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestFieldErrorPaths(t *testing.T) {
+	v := newValidator()
+
+	type Line struct {
+		Text string `firevault:"text,required"`
+	}
+
+	type Address struct {
+		Lines []Line          `firevault:"lines"`
+		Notes map[string]Line `firevault:"notes"`
+	}
+
+	type TestStruct struct {
+		Address *Address `firevault:"address"`
+	}
+
+	tests := []struct {
+		name           string
+		data           *TestStruct
+		wantPath       string
+		wantStructPath string
+	}{
+		{
+			name:           "Slice element",
+			data:           &TestStruct{&Address{Lines: []Line{{"a"}, {"b"}, {}}}},
+			wantPath:       "address.lines[2].text",
+			wantStructPath: "Address.Lines[2].Text",
+		},
+		{
+			name:           "Map element",
+			data:           &TestStruct{&Address{Notes: map[string]Line{"home": {}}}},
+			wantPath:       "address.notes.home.text",
+			wantStructPath: "Address.Notes.home.Text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.validate(context.Background(), tt.data, validationOpts{method: create})
+
+			var fe FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("Expected FieldError, got %v", err)
+			}
+			if fe.Path() != tt.wantPath {
+				t.Errorf("FieldError.Path() = %s, want %s", fe.Path(), tt.wantPath)
+			}
+			if fe.StructPath() != tt.wantStructPath {
+				t.Errorf("FieldError.StructPath() = %s, want %s", fe.StructPath(), tt.wantStructPath)
+			}
+			if fe.Field() != "text" || fe.StructField() != "Text" {
+				t.Errorf("Unexpected field names %s and %s", fe.Field(), fe.StructField())
+			}
+		})
+	}
+}
+
+func TestDiveRules(t *testing.T) {
+	v := newValidator()
+
+	upper := func(_ context.Context, _ string, value reflect.Value) (interface{}, error) {
+		return strings.ToUpper(value.String()), nil
+	}
+
+	err := v.registerTransformation("upper", upper)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	type TestStruct struct {
+		Tags    []string            `firevault:"tags,omitempty,max=3,dive,min=2,max=5"`
+		Codes   [2]string           `firevault:"codes,dive,transform=upper"`
+		Matrix  [][]int             `firevault:"matrix,omitempty,dive,min=1,dive,max=9"`
+		Scores  map[string]int      `firevault:"scores,omitempty,dive,keys,min=2,endkeys,max=100"`
+		Names   map[string]string   `firevault:"names,omitempty,dive,keys,transform=upper,endkeys,required"`
+		Aliases []*string           `firevault:"aliases,omitempty,dive,omitempty,min=2"`
+		Bad     map[string][]string `firevault:"bad,omitempty,dive,keys,min=1"`
+	}
+
+	alias := "ab"
+
+	tests := []struct {
+		name     string
+		data     *TestStruct
+		wantErr  bool
+		wantPath string
+		wantTag  string
+	}{
+		{
+			name: "Valid elements",
+			data: &TestStruct{
+				Tags:    []string{"go", "rust"},
+				Matrix:  [][]int{{1, 2}, {9}},
+				Scores:  map[string]int{"ab": 100},
+				Aliases: []*string{nil, &alias},
+			},
+		},
+		{
+			name:    "Too many elements",
+			data:    &TestStruct{Tags: []string{"aa", "bb", "cc", "dd"}},
+			wantErr: true, wantPath: "tags", wantTag: "max=3",
+		},
+		{
+			name:    "Invalid element",
+			data:    &TestStruct{Tags: []string{"go", "toolong"}},
+			wantErr: true, wantPath: "tags[1]", wantTag: "max=5",
+		},
+		{
+			name:    "Invalid nested element",
+			data:    &TestStruct{Matrix: [][]int{{1}, {2, 10}}},
+			wantErr: true, wantPath: "matrix[1][1]", wantTag: "max=9",
+		},
+		{
+			name:    "Invalid nested slice",
+			data:    &TestStruct{Matrix: [][]int{{1}, {}}},
+			wantErr: true, wantPath: "matrix[1]", wantTag: "min=1",
+		},
+		{
+			name:    "Invalid map key",
+			data:    &TestStruct{Scores: map[string]int{"a": 1}},
+			wantErr: true, wantPath: "scores.a", wantTag: "min=2",
+		},
+		{
+			name:    "Invalid map value",
+			data:    &TestStruct{Scores: map[string]int{"ab": 101}},
+			wantErr: true, wantPath: "scores.ab", wantTag: "max=100",
+		},
+		{
+			name:    "Invalid pointer element",
+			data:    &TestStruct{Aliases: []*string{new(string), &[]string{"a"}[0]}},
+			wantErr: true, wantPath: "aliases[1]", wantTag: "min=2",
+		},
+		{
+			name:    "Missing endkeys",
+			data:    &TestStruct{Bad: map[string][]string{"a": nil}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.validate(context.Background(), tt.data, validationOpts{method: create})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validator.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantPath == "" {
+				return
+			}
+
+			var fe FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("Expected FieldError, got %v", err)
+			}
+			if fe.Path() != tt.wantPath || fe.Tag() != tt.wantTag {
+				t.Errorf("Got error on %s with tag %s, want %s with tag %s", fe.Path(), fe.Tag(), tt.wantPath, tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("Transformations", func(t *testing.T) {
+		data := &TestStruct{
+			Codes: [2]string{"ab", "cd"},
+			Names: map[string]string{"bob": "Bob"},
+		}
+
+		result, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data.Codes != [2]string{"AB", "CD"} {
+			t.Errorf("Expected codes to be transformed, got %v", data.Codes)
+		}
+		if !reflect.DeepEqual(data.Names, map[string]string{"BOB": "Bob"}) {
+			t.Errorf("Expected name keys to be transformed, got %v", data.Names)
+		}
+		if !reflect.DeepEqual(result["codes"], []interface{}{"AB", "CD"}) {
+			t.Errorf("Expected transformed codes in result, got %v", result["codes"])
+		}
+	})
+
+	t.Run("Collect all errors", func(t *testing.T) {
+		data := &TestStruct{Tags: []string{"a", "ok", "b"}, Scores: map[string]int{"a": 1, "b": 200}}
+
+		_, err := v.validate(context.Background(), data, validationOpts{method: create, collectAllErrors: true})
+
+		var valErrs ValidationErrors
+		if !errors.As(err, &valErrs) {
+			t.Fatalf("Expected ValidationErrors, got %v", err)
+		}
+
+		paths := make([]string, 0, len(valErrs))
+		for _, fe := range valErrs {
+			paths = append(paths, fe.Path())
+		}
+
+		want := []string{"tags[0]", "tags[2]", "scores.a", "scores.b"}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("Got errors on %v, want %v", paths, want)
+		}
+	})
+}
+
+var errInvalidRange = errors.New("min must be less than max")
+
+type rangeStruct struct {
+	Min int `firevault:"min"`
+	Max int `firevault:"max"`
+}
+
+func (r *rangeStruct) Validate(_ context.Context, method string) error {
+	if method == string(update) {
+		return nil
+	}
+
+	if r.Min >= r.Max {
+		return errInvalidRange
+	}
+
+	return nil
+}
+
+func TestStructValidation(t *testing.T) {
+	v := newValidator()
+
+	type Address struct {
+		Line1 string `firevault:"line1"`
+		Line2 string `firevault:"line2"`
+	}
+
+	type TestStruct struct {
+		Name    string        `firevault:"name,required"`
+		Range   rangeStruct   `firevault:"range"`
+		Ranges  []rangeStruct `firevault:"ranges"`
+		Address *Address      `firevault:"address,omitempty"`
+		// map values (and arrays in them) aren't addressable
+		RangeMap   map[string]rangeStruct    `firevault:"range_map"`
+		RangePairs map[string][2]rangeStruct `firevault:"range_pairs"`
+	}
+
+	err := v.registerStructValidation(
+		reflect.TypeFor[*Address](),
+		func(_ context.Context, _ string, value reflect.Value) error {
+			if value.FieldByName("Line2").String() != "" && value.FieldByName("Line1").String() == "" {
+				return errors.New("line1 is required with line2")
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     *TestStruct
+		method   methodType
+		wantErr  bool
+		wantPath string
+	}{
+		{
+			name:   "Valid",
+			data:   &TestStruct{Name: "a", Range: rangeStruct{1, 2}, Address: &Address{"1 High St", "Flat 2"}},
+			method: create,
+		},
+		{
+			name:     "Invalid nested struct",
+			data:     &TestStruct{Name: "a", Range: rangeStruct{2, 1}},
+			method:   create,
+			wantErr:  true,
+			wantPath: "range",
+		},
+		{
+			name:   "Skipped for method",
+			data:   &TestStruct{Name: "a", Range: rangeStruct{2, 1}},
+			method: update,
+		},
+		{
+			name:     "Invalid slice element",
+			data:     &TestStruct{Name: "a", Range: rangeStruct{1, 2}, Ranges: []rangeStruct{{1, 2}, {3, 3}}},
+			method:   create,
+			wantErr:  true,
+			wantPath: "ranges[1]",
+		},
+		{
+			name: "Invalid map value",
+			data: &TestStruct{
+				Name:     "a",
+				Range:    rangeStruct{1, 2},
+				RangeMap: map[string]rangeStruct{"a": {1, 2}, "b": {3, 3}},
+			},
+			method:   create,
+			wantErr:  true,
+			wantPath: "range_map.b",
+		},
+		{
+			name: "Invalid array in map",
+			data: &TestStruct{
+				Name:       "a",
+				Range:      rangeStruct{1, 2},
+				RangePairs: map[string][2]rangeStruct{"a": {{1, 2}, {3, 1}}},
+			},
+			method:   create,
+			wantErr:  true,
+			wantPath: "range_pairs.a[1]",
+		},
+		{
+			name:     "Invalid registered type",
+			data:     &TestStruct{Name: "a", Range: rangeStruct{1, 2}, Address: &Address{Line2: "Flat 2"}},
+			method:   create,
+			wantErr:  true,
+			wantPath: "address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.validate(context.Background(), tt.data, validationOpts{method: tt.method})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validator.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				return
+			}
+
+			var fe FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("Expected FieldError, got %v", err)
+			}
+			if fe.Path() != tt.wantPath || fe.Code() != "failed-struct-validation" {
+				t.Errorf("Got %s error on %s, want failed-struct-validation on %s", fe.Code(), fe.Path(), tt.wantPath)
+			}
+		})
+	}
+
+	t.Run("Wrapped error", func(t *testing.T) {
+		_, err := v.validate(context.Background(), &rangeStruct{2, 1}, validationOpts{method: create})
+		if !errors.Is(err, errInvalidRange) {
+			t.Errorf("Expected error to wrap errInvalidRange, got %v", err)
+		}
+
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Field() != "rangeStruct" || fe.Path() != "" {
+			t.Errorf("Expected top-level FieldError, got %v", err)
+		}
+	})
+
+	t.Run("Field rules first", func(t *testing.T) {
+		_, err := v.validate(context.Background(), &TestStruct{Range: rangeStruct{2, 1}}, validationOpts{method: create})
+
+		var fe FieldError
+		if !errors.As(err, &fe) || fe.Path() != "name" {
+			t.Errorf("Expected name's error first, got %v", err)
+		}
+	})
+
+	t.Run("Invalid type", func(t *testing.T) {
+		err := v.registerStructValidation(reflect.TypeFor[string](), func(context.Context, string, reflect.Value) error {
+			return nil
+		})
+		if err == nil {
+			t.Error("Expected error for non-struct type")
+		}
+	})
+}
+
+func TestDefaultValues(t *testing.T) {
+	v := newValidator()
+
+	type Status string
+
+	type TestStruct struct {
+		Name      string    `firevault:"name,default=anonymous,min=3"`
+		Status    Status    `firevault:"status,default=draft,default_update=edited"`
+		Count     *int      `firevault:"count,default=1"`
+		Ratio     float64   `firevault:"ratio,omitempty,default_create=0.1"`
+		Active    bool      `firevault:"active,default=true"`
+		Level     uint8     `firevault:"level,default=7"`
+		StartedAt time.Time `firevault:"started_at,default=2006-01-02|2024-05-01"`
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		data := &TestStruct{}
+
+		result, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data.Name != "anonymous" || data.Status != "draft" || data.Ratio != 0.1 || !data.Active || data.Level != 7 {
+			t.Errorf("Expected defaults to be written back to the struct, got %+v", data)
+		}
+		if data.Count == nil || *data.Count != 1 {
+			t.Errorf("Expected count to point to 1, got %v", data.Count)
+		}
+		if !data.StartedAt.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected default time, got %v", data.StartedAt)
+		}
+		if result["name"] != "anonymous" || result["ratio"] != 0.1 {
+			t.Errorf("Expected defaults in result, got %v", result)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		data := &TestStruct{Name: "Bob"}
+
+		result, err := v.validate(context.Background(), data, validationOpts{method: update})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data.Name != "Bob" || data.Status != "edited" {
+			t.Errorf("Expected only empty fields to use method defaults, got %+v", data)
+		}
+		if _, ok := result["ratio"]; ok {
+			t.Errorf("Expected ratio to be omitted, got %v", result["ratio"])
+		}
+	})
+
+	t.Run("Allowed empty fields", func(t *testing.T) {
+		data := &TestStruct{}
+
+		_, err := v.validate(
+			context.Background(),
+			data,
+			validationOpts{method: create, emptyFieldsAllowed: []string{"active"}},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data.Active {
+			t.Error("Expected active to be left empty")
+		}
+	})
+
+	t.Run("Map of structs", func(t *testing.T) {
+		type Item struct {
+			Name string `firevault:"name,default=x"`
+		}
+
+		data := &struct {
+			Items map[string]Item `firevault:"items"`
+		}{map[string]Item{"a": {}, "b": {"y"}}}
+
+		result, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data.Items["a"].Name != "x" || data.Items["b"].Name != "y" {
+			t.Errorf("Expected defaults to be written back to the map, got %+v", data.Items)
+		}
+
+		items := result["items"].(map[string]interface{})
+		if items["a"].(map[string]interface{})["name"] != "x" {
+			t.Errorf("Expected default in result, got %v", items)
+		}
+	})
+
+	t.Run("Map of arrays", func(t *testing.T) {
+		type Item struct {
+			Name string `firevault:"name,default=x"`
+		}
+
+		data := &struct {
+			Items map[string][1]Item `firevault:"items"`
+		}{map[string][1]Item{"a": {}}}
+
+		_, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data.Items["a"][0].Name != "x" {
+			t.Errorf("Expected defaults to be written back to the map, got %+v", data.Items)
+		}
+	})
+
+	invalidTests := []struct {
+		name string
+		data interface{}
+	}{
+		{"Invalid int", &struct {
+			Count int `firevault:"count,default=abc"`
+		}{}},
+		{"Overflow", &struct {
+			Count int8 `firevault:"count,default=300"`
+		}{}},
+		{"Unsupported type", &struct {
+			Tags []string `firevault:"tags,default=a"`
+		}{}},
+	}
+
+	for _, tt := range invalidTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.validate(context.Background(), tt.data, validationOpts{method: create})
+			if err == nil {
+				t.Error("Expected error for invalid default")
+			}
+		})
+	}
+}
+
+func TestBuiltInValidations(t *testing.T) {
+	v := newValidator()
+
+	err := v.registerRegex("postcode", "^[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}$")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rule      string
+		value     interface{}
+		param     string
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Len string valid", "len", "abc", "3", true, false},
+		{"Len string invalid", "len", "abcd", "3", false, false},
+		{"Len slice valid", "len", []int{1, 2}, "2", true, false},
+		{"Len number valid", "len", 5, "5", true, false},
+		{"Len missing param", "len", "abc", "", false, true},
+		{"Eq string valid", "eq", "abc", "abc", true, false},
+		{"Eq string invalid", "eq", "abc", "3", false, false},
+		{"Eq number valid", "eq", 3.5, "3.5", true, false},
+		{"Eq bool valid", "eq", true, "true", true, false},
+		{"Eq map length valid", "eq", map[string]int{"a": 1}, "1", true, false},
+		{"Ne valid", "ne", "abc", "abd", true, false},
+		{"Ne invalid", "ne", 3, "3", false, false},
+		{"Gt valid", "gt", 4, "3", true, false},
+		{"Gt invalid", "gt", 3, "3", false, false},
+		{"Gt uint valid", "gt", uint(4), "3", true, false},
+		{"Gt time valid", "gt", date, "2006-01-02|2024-04-30", true, false},
+		{"Gte valid", "gte", 3, "3", true, false},
+		{"Gte invalid", "gte", "ab", "3", false, false},
+		{"Lt valid", "lt", 2.5, "3", true, false},
+		{"Lt invalid", "lt", date, "2006-01-02|2024-05-01", false, false},
+		{"Lte valid", "lte", []string{"a"}, "1", true, false},
+		{"Lte invalid", "lte", 4, "3", false, false},
+		{"Lt unsupported type", "lt", true, "1", false, true},
+		{"Oneof valid", "oneof", "green", "red green blue", true, false},
+		{"Oneof invalid", "oneof", "gre", "red green blue", false, false},
+		{"Oneof number valid", "oneof", 2, "1 2 3", true, false},
+		{"Oneof unsupported type", "oneof", 2.5, "2.5", false, true},
+		{"URL valid", "url", "https://example.com/path?q=1", "", true, false},
+		{"URL invalid", "url", "example.com", "", false, false},
+		{"URI valid", "uri", "mailto:hello@example.com", "", true, false},
+		{"URI invalid", "uri", "not a uri", "", false, false},
+		{"UUID valid", "uuid", "123e4567-e89b-12d3-a456-426614174000", "", true, false},
+		{"UUID invalid", "uuid", "123e4567-e89b-12d3-a456", "", false, false},
+		{"Alpha valid", "alpha", "abcXYZ", "", true, false},
+		{"Alpha invalid", "alpha", "abc1", "", false, false},
+		{"Alphanum valid", "alphanum", "abc123", "", true, false},
+		{"Alphanum invalid", "alphanum", "abc-123", "", false, false},
+		{"Numeric string valid", "numeric", "-12.5", "", true, false},
+		{"Numeric number valid", "numeric", 12, "", true, false},
+		{"Numeric invalid", "numeric", "12a", "", false, false},
+		{"Hexcolor valid", "hexcolor", "#1a2B3c", "", true, false},
+		{"Hexcolor short valid", "hexcolor", "#fff", "", true, false},
+		{"Hexcolor invalid", "hexcolor", "#ggg", "", false, false},
+		{"E164 valid", "e164", "+447123456789", "", true, false},
+		{"E164 invalid", "e164", "07123456789", "", false, false},
+		{"ISO3166 valid", "iso3166", "GB", "", true, false},
+		{"ISO3166 invalid", "iso3166", "UK", "", false, false},
+		{"IP v4 valid", "ip", "192.168.0.1", "", true, false},
+		{"IP v6 valid", "ip", "2001:db8::1", "", true, false},
+		{"IP invalid", "ip", "256.0.0.1", "", false, false},
+		{"CIDR valid", "cidr", "10.0.0.0/8", "", true, false},
+		{"CIDR invalid", "cidr", "10.0.0.0", "", false, false},
+		{"Contains valid", "contains", "firevault", "vault", true, false},
+		{"Contains invalid", "contains", "firevault", "store", false, false},
+		{"Startswith valid", "startswith", "firevault", "fire", true, false},
+		{"Startswith invalid", "startswith", "firevault", "vault", false, false},
+		{"Endswith valid", "endswith", "firevault", "vault", true, false},
+		{"Endswith invalid", "endswith", "firevault", "fire", false, false},
+		{"Endswith unsupported type", "endswith", 1, "1", false, true},
+		{"Regex valid", "regex", "SW1A 1AA", "postcode", true, false},
+		{"Regex invalid", "regex", "12345", "postcode", false, false},
+		{"Regex unknown", "regex", "12345", "zip", false, true},
+		{"Unique slice valid", "unique", []string{"a", "b"}, "", true, false},
+		{"Unique slice invalid", "unique", []int{1, 2, 1}, "", false, false},
+		{"Unique map invalid", "unique", map[string]int{"a": 1, "b": 1}, "", false, false},
+		{"Unique unsupported type", "unique", "aa", "", false, true},
+		{"JSON valid", "json", `{"a":[1,2]}`, "", true, false},
+		{"JSON invalid", "json", `{"a":`, "", false, false},
+		{"Base64 valid", "base64", "aGVsbG8=", "", true, false},
+		{"Base64 invalid", "base64", "aGVsbG8", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, ok := v.validations[tt.rule]
+			if !ok {
+				t.Fatalf("Validation rule %s not found", tt.rule)
+			}
+
+			valid, err := validator(context.Background(), "test", reflect.ValueOf(tt.value), tt.param)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validator.%s() error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf(
+					"validator.%s() with value %v and param %s: got %v, want %v",
+					tt.rule,
+					tt.value,
+					tt.param,
+					valid,
+					tt.wantValid,
+				)
+			}
+		})
+	}
+
+	t.Run("Invalid regex", func(t *testing.T) {
+		if err := v.registerRegex("bad", "[a-"); err == nil {
+			t.Error("Expected error for invalid regex")
+		}
+	})
+}
+
+func TestBuiltInTransformations(t *testing.T) {
+	v := newValidator()
+
+	type Email string
+
+	local := time.FixedZone("UTC+2", 2*60*60)
+
+	tests := []struct {
+		name    string
+		rule    string
+		value   interface{}
+		param   string
+		want    interface{}
+		wantErr bool
+	}{
+		{"Trim", "trim", "  hello \n", "", "hello", false},
+		{"Trim unsupported type", "trim", 1, "", nil, true},
+		{"Lowercase", "lowercase", "HeLLo", "", "hello", false},
+		{"Lowercase named type", "lowercase", Email("A@B.COM"), "", Email("a@b.com"), false},
+		{"Uppercase", "uppercase", "HeLLo", "", "HELLO", false},
+		{"Title", "title", "hello wORLD", "", "Hello World", false},
+		{"Slugify", "slugify", "  Crème Brûlée: The Recipe! ", "", "creme-brulee-the-recipe", false},
+		{"Normalize email", "normalize_email", " Bob@Example.COM ", "", "bob@example.com", false},
+		{"Normalize phone", "normalize_phone_e164", "+44 (0)7123-456.789", "", "+447123456789", false},
+		{"Normalize phone prefix", "normalize_phone_e164", "00 44 (0)7123 456789", "", "+447123456789", false},
+		{"Normalize national phone", "normalize_phone_e164", "(0)7123 456789", "", "07123456789", false},
+		{"Unicode NFC", "unicode_nfc", "e\u0301", "", "\u00e9", false},
+		{"Truncate", "truncate", "héllo world", "5", "héllo", false},
+		{"Truncate short", "truncate", "hi", "5", "hi", false},
+		{"Truncate missing param", "truncate", "hello", "", nil, true},
+		{"Truncate negative param", "truncate", "hello", "-1", nil, true},
+		{"Round", "round", 3.14159, "2", 3.14, false},
+		{"Round whole", "round", 2.5, "", 3.0, false},
+		{"Round float32", "round", float32(1.26), "1", float32(1.3), false},
+		{"Round unsupported type", "round", 3, "1", nil, true},
+		{
+			"UTC",
+			"utc",
+			time.Date(2024, 5, 1, 12, 0, 0, 0, local),
+			"",
+			time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			false,
+		},
+		{"UTC unsupported type", "utc", "2024", "", nil, true},
+		{"Dedupe", "dedupe", []string{"a", "b", "a", "c", "b"}, "", []string{"a", "b", "c"}, false},
+		{"Dedupe unsupported type", "dedupe", "aa", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformation, ok := v.transformations[tt.rule]
+			if !ok {
+				t.Fatalf("Transformation rule %s not found", tt.rule)
+			}
+
+			got, err := transformation(context.Background(), "test", reflect.ValueOf(tt.value), tt.param)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("transformation.%s() error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transformation.%s() with value %v: got %#v, want %#v", tt.rule, tt.value, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Tags", func(t *testing.T) {
+		type TestStruct struct {
+			Email *string  `firevault:"email,transform=normalize_email,email"`
+			Title string   `firevault:"title,transform=trim,transform=truncate=5,max=5"`
+			Tags  []string `firevault:"tags,transform=dedupe,max=2"`
+		}
+
+		email := " Bob@Example.com "
+		data := &TestStruct{Email: &email, Title: "  Hello world", Tags: []string{"a", "a", "b"}}
+
+		_, err := v.validate(context.Background(), data, validationOpts{method: create})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if *data.Email != "bob@example.com" || data.Title != "Hello" || len(data.Tags) != 2 {
+			t.Errorf("Expected values to be transformed, got %s, %s and %v", *data.Email, data.Title, data.Tags)
+		}
+	})
+}
+
+func TestStructPlanCache(t *testing.T) {
+	v := newValidator()
+
+	type TestStruct struct {
+		Name string `firevault:"name,is_bob"`
+	}
+
+	_, err := v.validate(context.Background(), &TestStruct{"bob"}, validationOpts{method: create})
+
+	var fe FieldError
+	if !errors.As(err, &fe) || fe.Code() != "unknown-validation" {
+		t.Fatalf("Expected unknown-validation error, got %v", err)
+	}
+
+	// registering the rule later should replace the cached plan
+	err = v.registerValidation(
+		"is_bob",
+		func(_ context.Context, _ string, value reflect.Value, _ string) (bool, error) {
+			return value.String() == "bob", nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = v.validate(context.Background(), &TestStruct{"bob"}, validationOpts{method: create})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		v := newValidator()
+		errs := make(chan error, 10)
+
+		for i := 0; i < cap(errs); i++ {
+			go func() {
+				_, err := v.validate(context.Background(), newBenchUser(), validationOpts{method: create})
+				errs <- err
+			}()
+		}
+
+		for i := 0; i < cap(errs); i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}
+	})
+}
+
+type benchAddress struct {
+	Line1    string `firevault:"line1,required,transform=trim,max=100"`
+	Line2    string `firevault:"line2,omitempty,max=100"`
+	City     string `firevault:"city,required,alpha"`
+	Postcode string `firevault:"postcode,required,alphanum,min=5,max=8"`
+	Country  string `firevault:"country,required,iso3166"`
+}
+
+type benchUser struct {
+	Name      string            `firevault:"name,required,min=3,max=50"`
+	Email     string            `firevault:"email,required,email,transform=lowercase"`
+	Phone     string            `firevault:"phone,omitempty,e164"`
+	Website   string            `firevault:"website,omitempty,url"`
+	Age       int               `firevault:"age,required,gte=18,lte=120"`
+	Score     float64           `firevault:"score,omitempty,gte=0,lte=100"`
+	Role      string            `firevault:"role,default=user,oneof=user admin"`
+	Bio       string            `firevault:"bio,omitempty,max=500"`
+	Tags      []string          `firevault:"tags,omitempty,max=10,dive,min=2,max=20"`
+	Labels    map[string]string `firevault:"labels,omitempty,dive,keys,alpha,endkeys,max=50"`
+	Address   benchAddress      `firevault:"address"`
+	Addresses []benchAddress    `firevault:"addresses,omitempty,max=5"`
+	CreatedAt time.Time         `firevault:"created_at,omitempty"`
+	Verified  bool              `firevault:"verified"`
+	Password  string            `firevault:"password,required,min=8"`
+	Confirm   string            `firevault:"confirm,eqfield=password"`
+}
+
+func newBenchUser() *benchUser {
+	address := benchAddress{"1 High Street", "Flat 2", "London", "SW1A1AA", "GB"}
+
+	return &benchUser{
+		Name:      "Bobby Donev",
+		Email:     "hello@bobbydonev.com",
+		Phone:     "+447123456789",
+		Website:   "https://bobbydonev.com",
+		Age:       26,
+		Score:     87.5,
+		Bio:       "Writes Go.",
+		Tags:      []string{"go", "firestore", "validation"},
+		Labels:    map[string]string{"team": "core", "tier": "gold"},
+		Address:   address,
+		Addresses: []benchAddress{address, address, address},
+		CreatedAt: time.Now(),
+		Password:  "correct-horse",
+		Confirm:   "correct-horse",
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	v := newValidator()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := v.validate(ctx, newBenchUser(), validationOpts{method: create})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	v := newValidator()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, err := v.validate(ctx, newBenchUser(), validationOpts{method: create})
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
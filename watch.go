@@ -0,0 +1,400 @@
+package firevault
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ChangeKind describes the kind of change to a document.
+type ChangeKind int
+
+const (
+	// DocumentAdded indicates that the document started matching
+	// the watched Query (or the watched document was created).
+	DocumentAdded ChangeKind = iota
+	// DocumentModified indicates that the document was changed.
+	DocumentModified
+	// DocumentRemoved indicates that the document stopped matching
+	// the watched Query (or the watched document was deleted).
+	DocumentRemoved
+)
+
+// the delays before resubscribing to a failed watch
+const (
+	minWatchBackoff = time.Second
+	maxWatchBackoff = time.Minute
+)
+
+// A Firevault Change describes a change to a watched document.
+type Change[T interface{}] struct {
+	// Kind is the kind of change.
+	Kind ChangeKind
+	// Doc is the document after the change (or before it,
+	// for removed documents).
+	Doc Document[T]
+	// OldIndex is the document's zero-based index in the
+	// results before the change, or -1 if it wasn't present.
+	OldIndex int
+	// NewIndex is the document's zero-based index in the
+	// results after the change, or -1 if it's no longer present.
+	NewIndex int
+}
+
+// watches a query, keeping the last known results to resume from
+type queryWatcher[T interface{}] struct {
+	collection *CollectionRef[T]
+	query      firestore.Query
+	fn         func([]Change[T]) error
+	known      map[string]knownDoc
+}
+
+// a document from the last known results of a watched query
+type knownDoc struct {
+	index    int
+	snapshot *firestore.DocumentSnapshot
+}
+
+// watches a single document, keeping its last known snapshot
+type docWatcher[T interface{}] struct {
+	collection *CollectionRef[T]
+	docRef     *firestore.DocumentRef
+	fn         func(Change[T]) error
+	last       *firestore.DocumentSnapshot
+}
+
+// an error which stops the watch, without resubscribing
+// (e.g. returned by the watch function)
+type stopError struct {
+	err error
+}
+
+// return the underlying error's message
+func (e *stopError) Error() string {
+	return e.err.Error()
+}
+
+// Watch listens for changes to the Firestore documents which match
+// provided Query, calling fn with the changes each time the results
+// change. The first call holds all matching documents (if any),
+// as added.
+//
+// Watch blocks until ctx is done (returning nil), fn returns an
+// error (returning the same error), or the listener fails with
+// an error which can't be retried.
+//
+// If the listener is interrupted (e.g. due to network issues),
+// it is resubscribed with an increasing delay. Changes which
+// happened in the meantime are delivered once it resumes, without
+// repeating documents which haven't changed.
+//
+// If the Query specifies IDs, the documents with those IDs are
+// watched (up to 30, Firestore's limit for "in" filters).
+func (c *CollectionRef[T]) Watch(ctx context.Context, query Query, fn func(changes []Change[T]) error) error {
+	if c == nil {
+		return errors.New("firevault: nil CollectionRef")
+	}
+
+	if fn == nil {
+		return errors.New("firevault: nil watch function")
+	}
+
+	builtQuery, err := c.filterQuery(query)
+	if err != nil {
+		return err
+	}
+
+	w := &queryWatcher[T]{collection: c, query: builtQuery, fn: fn}
+
+	return runWatch(ctx, w.subscribe)
+}
+
+// WatchDoc listens for changes to the Firestore document with
+// provided ID, calling fn each time it's created, changed or
+// deleted. If the document exists, the first call holds it, as
+// added.
+//
+// WatchDoc blocks until ctx is done (returning nil), fn returns
+// an error (returning the same error), or the listener fails with
+// an error which can't be retried.
+//
+// If the listener is interrupted (e.g. due to network issues),
+// it is resubscribed with an increasing delay. A change which
+// happened in the meantime is delivered once it resumes.
+func (c *CollectionRef[T]) WatchDoc(ctx context.Context, id string, fn func(change Change[T]) error) error {
+	if c == nil || c.ref == nil {
+		return errors.New("firevault: nil CollectionRef")
+	}
+
+	if fn == nil {
+		return errors.New("firevault: nil watch function")
+	}
+
+	docRef := c.ref.Doc(id)
+	if docRef == nil {
+		return errors.New("firevault: invalid document ID - " + id)
+	}
+
+	w := &docWatcher[T]{collection: c, docRef: docRef, fn: fn}
+
+	return runWatch(ctx, w.subscribe)
+}
+
+// run the subscription until ctx is done or it fails with an error
+// which can't be retried, resubscribing (with backoff) otherwise
+func runWatch(ctx context.Context, subscribe func(context.Context) (bool, error)) error {
+	backoff := minWatchBackoff
+
+	for {
+		received, err := subscribe(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var stopErr *stopError
+		if errors.As(err, &stopErr) {
+			return stopErr.err
+		}
+
+		if !isRetryableWatchError(err) {
+			return wrapError(err)
+		}
+
+		// the listener was healthy, so start backing off again
+		if received {
+			backoff = minWatchBackoff
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, maxWatchBackoff)
+	}
+}
+
+// checks if the listener can be resubscribed after the error
+func isRetryableWatchError(err error) bool {
+	if err == iterator.Done {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Internal, codes.ResourceExhausted,
+		codes.DeadlineExceeded, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// listen for query snapshots until the stream fails, reporting
+// whether any snapshot was received
+func (w *queryWatcher[T]) subscribe(ctx context.Context) (bool, error) {
+	it := w.query.Snapshots(ctx)
+	defer it.Stop()
+
+	resumed := w.known != nil
+	received := false
+
+	for {
+		snap, err := it.Next()
+		if err != nil {
+			return received, err
+		}
+
+		docSnaps, err := snap.Documents.GetAll()
+		if err != nil {
+			return received, err
+		}
+
+		var changes []Change[T]
+
+		// a resubscribed listener starts over, so diff against the last known results
+		if resumed && !received {
+			changes, err = w.resumeChanges(docSnaps)
+		} else {
+			changes, err = w.changes(snap.Changes)
+		}
+		if err != nil {
+			return received, &stopError{err}
+		}
+
+		received = true
+		w.known = make(map[string]knownDoc, len(docSnaps))
+
+		for i, docSnap := range docSnaps {
+			w.known[docSnap.Ref.Path] = knownDoc{i, docSnap}
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		err = w.fn(changes)
+		if err != nil {
+			return received, &stopError{err}
+		}
+	}
+}
+
+// convert firestore's changes to typed changes
+func (w *queryWatcher[T]) changes(docChanges []firestore.DocumentChange) ([]Change[T], error) {
+	changes := make([]Change[T], 0, len(docChanges))
+
+	for _, docChange := range docChanges {
+		kind := DocumentAdded
+
+		switch docChange.Kind {
+		case firestore.DocumentModified:
+			kind = DocumentModified
+		case firestore.DocumentRemoved:
+			kind = DocumentRemoved
+		}
+
+		change, err := w.collection.newChange(kind, docChange.Doc, docChange.OldIndex, docChange.NewIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// get the changes between the last known results and the current
+// ones, skipping documents which haven't changed
+func (w *queryWatcher[T]) resumeChanges(docSnaps []*firestore.DocumentSnapshot) ([]Change[T], error) {
+	var removed, changed []Change[T]
+	current := make(map[string]bool, len(docSnaps))
+
+	for i, docSnap := range docSnaps {
+		current[docSnap.Ref.Path] = true
+
+		prev, ok := w.known[docSnap.Ref.Path]
+		if ok && prev.snapshot.UpdateTime.Equal(docSnap.UpdateTime) {
+			continue
+		}
+
+		kind, oldIndex := DocumentAdded, -1
+		if ok {
+			kind, oldIndex = DocumentModified, prev.index
+		}
+
+		change, err := w.collection.newChange(kind, docSnap, oldIndex, i)
+		if err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, change)
+	}
+
+	for path, prev := range w.known {
+		if current[path] {
+			continue
+		}
+
+		change, err := w.collection.newChange(DocumentRemoved, prev.snapshot, prev.index, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		removed = append(removed, change)
+	}
+
+	// removals come first, as in firestore's changes
+	slices.SortFunc(removed, func(a, b Change[T]) int {
+		return a.OldIndex - b.OldIndex
+	})
+
+	return append(removed, changed...), nil
+}
+
+// listen for document snapshots until the stream fails, reporting
+// whether any snapshot was received
+func (w *docWatcher[T]) subscribe(ctx context.Context) (bool, error) {
+	it := w.docRef.Snapshots(ctx)
+	defer it.Stop()
+
+	received := false
+
+	for {
+		docSnap, err := it.Next()
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+
+		change, changed, err := w.change(docSnap)
+		if err != nil {
+			return received, &stopError{err}
+		}
+
+		if !changed {
+			continue
+		}
+
+		err = w.fn(change)
+		if err != nil {
+			return received, &stopError{err}
+		}
+	}
+}
+
+// get the change between the last known snapshot and the current
+// one, reporting whether the document has changed
+func (w *docWatcher[T]) change(docSnap *firestore.DocumentSnapshot) (Change[T], bool, error) {
+	last := w.last
+
+	var change Change[T]
+	var err error
+
+	switch {
+	case docSnap.Exists() && last == nil:
+		change, err = w.collection.newChange(DocumentAdded, docSnap, -1, 0)
+	case docSnap.Exists() && !last.UpdateTime.Equal(docSnap.UpdateTime):
+		change, err = w.collection.newChange(DocumentModified, docSnap, 0, 0)
+	case !docSnap.Exists() && last != nil:
+		change, err = w.collection.newChange(DocumentRemoved, last, 0, -1)
+	default:
+		return Change[T]{}, false, nil
+	}
+	if err != nil {
+		return Change[T]{}, false, err
+	}
+
+	w.last = nil
+	if docSnap.Exists() {
+		w.last = docSnap
+	}
+
+	return change, true, nil
+}
+
+// create a change, decoding the document's snapshot
+func (c *CollectionRef[T]) newChange(
+	kind ChangeKind,
+	docSnap *firestore.DocumentSnapshot,
+	oldIndex int,
+	newIndex int,
+) (Change[T], error) {
+	doc, err := c.decodeDoc(docSnap)
+	if err != nil {
+		return Change[T]{}, err
+	}
+
+	return Change[T]{kind, doc, oldIndex, newIndex}, nil
+}
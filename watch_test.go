@@ -0,0 +1,96 @@
+package firevault
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// create a snapshot of a document (without data) in the users collection
+func testSnapshot(id string, updateTime time.Time) *firestore.DocumentSnapshot {
+	parent := &firestore.CollectionRef{ID: "users", Path: "projects/p/databases/d/documents/users"}
+	docRef := &firestore.DocumentRef{Parent: parent, ID: id, Path: parent.Path + "/" + id}
+
+	return &firestore.DocumentSnapshot{Ref: docRef, UpdateTime: updateTime}
+}
+
+type testChange struct {
+	kind     ChangeKind
+	id       string
+	oldIndex int
+	newIndex int
+}
+
+// simplify changes for comparison
+func toTestChanges[T interface{}](changes []Change[T]) []testChange {
+	result := make([]testChange, 0, len(changes))
+
+	for _, change := range changes {
+		result = append(result, testChange{change.Kind, change.Doc.ID, change.OldIndex, change.NewIndex})
+	}
+
+	return result
+}
+
+func TestResumeChanges(t *testing.T) {
+	type User struct {
+		Name string `firevault:"name"`
+	}
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	w := &queryWatcher[User]{
+		collection: &CollectionRef[User]{},
+		known: map[string]knownDoc{
+			"projects/p/databases/d/documents/users/a": {0, testSnapshot("a", t1)},
+			"projects/p/databases/d/documents/users/b": {1, testSnapshot("b", t1)},
+			"projects/p/databases/d/documents/users/c": {2, testSnapshot("c", t1)},
+		},
+	}
+
+	changes, err := w.resumeChanges([]*firestore.DocumentSnapshot{
+		testSnapshot("a", t1),
+		testSnapshot("c", t2),
+		testSnapshot("d", t1),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []testChange{
+		{DocumentRemoved, "b", 1, -1},
+		{DocumentModified, "c", 2, 1},
+		{DocumentAdded, "d", -1, 2},
+	}
+	if got := toTestChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Errorf("queryWatcher.resumeChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestIsRetryableWatchError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Stream ended", iterator.Done, true},
+		{"Unavailable", status.Error(codes.Unavailable, "unavailable"), true},
+		{"Permission denied", status.Error(codes.PermissionDenied, "denied"), false},
+		{"Other error", errors.New("decode failed"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableWatchError(tt.err); got != tt.want {
+				t.Errorf("isRetryableWatchError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}